@@ -28,6 +28,7 @@ type Config struct {
 	AzureStorageEndpoint string
 	Environment          string
 	Queue                string
+	SelectionWeightsFile string
 }
 
 var (
@@ -71,6 +72,7 @@ func LoadConfig() *Config {
 		AzureStorageEndpoint: viper.GetString("AZURE_STORAGE_ENDPOINT"),
 		Environment:          viper.GetString("ENVIRONMENT"),
 		Queue:                viper.GetString("QUEUE"),
+		SelectionWeightsFile: viper.GetString("SELECTION_WEIGHTS_FILE"),
 	}
 
 	err = InitBlobClient()
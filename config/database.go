@@ -35,12 +35,24 @@ func ConnectDatabase() {
 		&models.WorkoutPlan{},
 		&models.WorkoutPlanDay{},
 		&models.WorkoutPlanExercise{},
+		&models.WorkoutPlanWeek{},
+		&models.WorkoutPlanGroup{},
+		&models.WorkoutSession{},
+		&models.WorkoutSessionSet{},
+		&models.PersonalRecord{},
+		&models.SchemaMigration{},
+		&models.SplitDefinition{},
+		&models.SplitFocus{},
+		&models.FocusMapping{},
+		&models.UserExercisePreference{},
 	)
 	if err != nil {
 		log.Fatalf("Failed to migrate database tables: %v", err)
 	}
 	log.Println("✅ Database tables migrated successfully")
 
+	RunDataMigrations()
+
 	err = TestBlobStorageConnection()
 	if err != nil {
 		log.Fatalf("Failed to connect to Azure Blob Storage: %v", err)
@@ -67,12 +79,22 @@ func TestBlobStorageConnection() error {
 
 func ResetEntireDatabase() {
 	tables := []string{
+		"personal_records",
+		"workout_session_sets",
+		"workout_sessions",
 		"workout_plan_exercises",
+		"workout_plan_groups",
 		"workout_plan_days",
+		"workout_plan_weeks",
 		"workout_plans",
+		"split_focus",
+		"split_definitions",
+		"focus_mappings",
+		"user_exercise_preferences",
 		"profiles",
 		"exercises",
 		"users",
+		"schema_migrations",
 	}
 
 	for _, table := range tables {
@@ -83,7 +105,10 @@ func ResetEntireDatabase() {
 			log.Printf("✅ Deleted from table: %s", table)
 		}
 
-		// Reset identity
+		// Reset identity; schema_migrations is keyed by migration ID and has none
+		if table == "schema_migrations" {
+			continue
+		}
 		if err := DB.Exec("DBCC CHECKIDENT ('" + table + "', RESEED, 0);").Error; err != nil {
 			log.Printf("⚠️ Failed to reseed %s: %v", table, err)
 		} else {
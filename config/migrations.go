@@ -0,0 +1,115 @@
+package config
+
+import (
+	"encoding/csv"
+	"fmt"
+	"log"
+	"os"
+	"strconv"
+	"strings"
+	"wellnesspath/models"
+
+	"gorm.io/gorm"
+)
+
+type dataMigration struct {
+	ID  string
+	Run func(tx *gorm.DB) error
+}
+
+// dataMigrations backfill existing rows after AutoMigrate has added new columns.
+// Each one runs once and is recorded in schema_migrations; append new steps at the end.
+var dataMigrations = []dataMigration{
+	{
+		ID: "2026-10-activate-latest-plan",
+		Run: func(tx *gorm.DB) error {
+			// Before plan versioning only the newest non-deleted plan was in use
+			return tx.Exec(`
+				UPDATE workout_plans SET is_active = 1
+				WHERE id IN (
+					SELECT MAX(id) FROM workout_plans WHERE is_deleted = 0 GROUP BY user_id
+				)`).Error
+		},
+	},
+	{
+		ID: "2026-10-rest-day-types",
+		Run: func(tx *gorm.DB) error {
+			// Rest days used to be marked by their focus and a placeholder exercise with exercise_id 0
+			if err := tx.Exec(`UPDATE workout_plan_days SET day_type = 'rest' WHERE focus = 'Rest'`).Error; err != nil {
+				return err
+			}
+			return tx.Exec(`DELETE FROM workout_plan_exercises WHERE exercise_id = 0`).Error
+		},
+	},
+	{
+		ID:  "2026-10-exercise-ratings",
+		Run: backfillExerciseRatings,
+	},
+	{
+		ID: "2026-10-progression-markers",
+		Run: func(tx *gorm.DB) error {
+			// Progression used to read sets logged after updated_at; keep ignoring those older sets
+			return tx.Exec(`
+				UPDATE workout_plan_exercises SET progressed_at = updated_at
+				WHERE progressed_at IS NULL AND updated_at > created_at`).Error
+		},
+	},
+}
+
+// backfillExerciseRatings copies the ratings in exercises.csv onto catalogues seeded before the
+// seeder read them. Exercises are matched by name; ratings already set are left alone.
+func backfillExerciseRatings(tx *gorm.DB) error {
+	file, err := os.Open("exercises.csv")
+	if err != nil {
+		return fmt.Errorf("failed to open exercise catalogue: %w", err)
+	}
+	defer file.Close()
+
+	reader := csv.NewReader(file)
+	reader.Comma = ';'
+	records, err := reader.ReadAll()
+	if err != nil {
+		return fmt.Errorf("failed to read exercise catalogue: %w", err)
+	}
+
+	for i, record := range records {
+		if i == 0 || len(record) < 9 {
+			continue
+		}
+		name := strings.TrimSpace(record[0])
+		rating, err := strconv.ParseFloat(strings.Replace(strings.TrimSpace(record[8]), ",", ".", 1), 64)
+		if name == "" || err != nil || rating <= 0 {
+			continue
+		}
+		err = tx.Model(&models.Exercise{}).
+			Where("name = ? AND rating = 0", name).
+			Update("rating", rating).Error
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func RunDataMigrations() {
+	for _, migration := range dataMigrations {
+		var applied int64
+		if err := DB.Model(&models.SchemaMigration{}).Where("id = ?", migration.ID).Count(&applied).Error; err != nil {
+			log.Fatalf("Failed to read migration state: %v", err)
+		}
+		if applied > 0 {
+			continue
+		}
+
+		err := DB.Transaction(func(tx *gorm.DB) error {
+			if err := migration.Run(tx); err != nil {
+				return err
+			}
+			return tx.Create(&models.SchemaMigration{ID: migration.ID}).Error
+		})
+		if err != nil {
+			log.Fatalf("Failed to run data migration %s: %v", migration.ID, err)
+		}
+		log.Printf("✅ Applied data migration: %s", migration.ID)
+	}
+}
@@ -0,0 +1,37 @@
+package controllers
+
+import (
+	"errors"
+	"fmt"
+	"strconv"
+	"wellnesspath/helpers"
+	"wellnesspath/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetVolumeAnalytics(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	weeks := helpers.DefaultAnalyticsWeeks
+	if weeksStr := c.Query("weeks"); weeksStr != "" {
+		parsed, err := strconv.Atoi(weeksStr)
+		if err != nil || parsed < 1 || parsed > helpers.MaxAnalyticsWeeks {
+			helpers.ValidationErrorResponse(c, "Invalid weeks", fmt.Sprintf("weeks must be between 1 and %d", helpers.MaxAnalyticsWeeks))
+			return
+		}
+		weeks = parsed
+	}
+
+	result, err := (&services.AnalyticsService{}).GetVolume(userID.(uint64), weeks)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Volume analytics retrieved successfully", result)
+}
@@ -0,0 +1,66 @@
+package controllers
+
+import (
+	"errors"
+	"net/http"
+	"strings"
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetCalendarFeed(c *gin.Context) {
+	token := strings.TrimSuffix(c.Param("token"), ".ics")
+
+	ics, err := (&services.CalendarService{}).RenderFeed(token)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	c.Data(http.StatusOK, "text/calendar; charset=utf-8", []byte(ics))
+}
+
+func GetCalendarFeedToken(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	feed, err := (&services.CalendarService{}).GetFeed(userID.(uint64))
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Calendar feed retrieved successfully", withFeedURL(c, feed))
+}
+
+func RotateCalendarFeedToken(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	feed, err := (&services.CalendarService{}).RotateFeedToken(userID.(uint64))
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Calendar feed token rotated successfully", withFeedURL(c, feed))
+}
+
+// withFeedURL turns the feed path into an absolute URL for the host the request came in on.
+func withFeedURL(c *gin.Context, feed dto.CalendarFeedResponse) dto.CalendarFeedResponse {
+	scheme := "http"
+	if c.Request.TLS != nil || strings.EqualFold(c.GetHeader("X-Forwarded-Proto"), "https") {
+		scheme = "https"
+	}
+	feed.FeedURL = scheme + "://" + c.Request.Host + feed.FeedPath
+	return feed
+}
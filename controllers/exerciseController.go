@@ -1,7 +1,9 @@
 package controllers
 
 import (
+	"errors"
 	"strconv"
+	"wellnesspath/dto"
 	"wellnesspath/helpers"
 	"wellnesspath/services"
 
@@ -57,3 +59,68 @@ func GetExerciseVideo(c *gin.Context) {
 
 	helpers.SuccessResponseWithData(c, "Workout for today fetched successfully", plan)
 }
+
+func GetExercisePreferences(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := (&services.ExercisePreferenceService{}).GetPreferences(userID.(uint64))
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "exercise preferences retrieved successfully", result)
+}
+
+func SetExercisePreference(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	exerciseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	var req dto.ExercisePreferenceRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := (&services.ExercisePreferenceService{}).SetPreference(userID.(uint64), exerciseID, req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "exercise preference saved successfully", result)
+}
+
+func RemoveExercisePreference(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	exerciseID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	if err := (&services.ExercisePreferenceService{}).RemovePreference(userID.(uint64), exerciseID); err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, "exercise preference removed successfully")
+}
@@ -0,0 +1,73 @@
+package controllers
+
+import (
+	"strconv"
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetFocusMappings(c *gin.Context) {
+	result, err := (&services.FocusMappingService{}).GetFocusMappings()
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Focus mappings retrieved successfully", result)
+}
+
+func CreateFocusMapping(c *gin.Context) {
+	var req dto.FocusMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := (&services.FocusMappingService{}).CreateFocusMapping(req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Focus mapping created successfully", result)
+}
+
+func UpdateFocusMapping(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	var req dto.FocusMappingRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := (&services.FocusMappingService{}).UpdateFocusMapping(id, req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Focus mapping updated successfully", result)
+}
+
+func DeleteFocusMapping(c *gin.Context) {
+	id, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	if err := (&services.FocusMappingService{}).DeleteFocusMapping(id); err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, "Focus mapping deleted successfully")
+}
@@ -2,6 +2,7 @@ package controllers
 
 import (
 	"errors"
+	"fmt"
 	"strconv"
 	"wellnesspath/dto"
 	"wellnesspath/helpers"
@@ -17,13 +18,35 @@ func GenerateWorkoutPlan(c *gin.Context) {
 		return
 	}
 
-	err := (&services.PlanService{}).GenerateWorkoutPlan(userID.(uint64))
+	var options dto.GeneratePlanOptions
+	if err := c.ShouldBindQuery(&options); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid seed", err.Error())
+		return
+	}
+
+	result, err := (&services.PlanService{}).GenerateWorkoutPlan(userID.(uint64), options)
 	if err != nil {
 		helpers.ErrorResponse(c, err)
 		return
 	}
 
-	helpers.SuccessResponse(c, "Workout plan generated successfully")
+	helpers.SuccessResponseWithData(c, "Workout plan generated successfully", result)
+}
+
+func ShuffleWorkoutPlan(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := (&services.PlanService{}).ShufflePlan(userID.(uint64))
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Workout plan shuffled successfully", result)
 }
 
 // SEPERATE FUNCTION ***
@@ -44,13 +67,19 @@ func InitializeWorkoutPlan(c *gin.Context) {
 }
 
 func CreateWorkoutPlanDays(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
 	var input dto.CreateDaysRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		helpers.ErrorResponse(c, err)
 		return
 	}
 
-	days, err := (&services.PlanService{}).CreateWorkoutPlanDays(input)
+	days, err := (&services.PlanService{}).CreateWorkoutPlanDays(userID.(uint64), input)
 	if err != nil {
 		helpers.ErrorResponse(c, err)
 		return
@@ -60,13 +89,19 @@ func CreateWorkoutPlanDays(c *gin.Context) {
 }
 
 func InsertExercisesToDays(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
 	var input dto.InsertExercisesRequest
 	if err := c.ShouldBindJSON(&input); err != nil {
 		helpers.ErrorResponse(c, err)
 		return
 	}
 
-	if err := (&services.PlanService{}).InsertExercisesToDays(input); err != nil {
+	if err := (&services.PlanService{}).InsertExercisesToDays(userID.(uint64), input); err != nil {
 		helpers.ErrorResponse(c, err)
 		return
 	}
@@ -105,7 +140,12 @@ func GetPlanByUserID(c *gin.Context) {
 		return
 	}
 
-	plan, err := (&services.PlanService{}).GetPlanByUserID(userID)
+	weeks, ok := parseScheduleWeeks(c)
+	if !ok {
+		return
+	}
+
+	plan, err := (&services.PlanService{}).GetPlanByUserID(userID, weeks)
 	if err != nil {
 		helpers.ErrorResponse(c, err)
 		return
@@ -197,6 +237,56 @@ func UpdateExerciseReps(c *gin.Context) {
 	helpers.SuccessResponse(c, "Reps updated successfully")
 }
 
+func UpdateExerciseTargets(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	var req dto.EditTargetsRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	err := (&services.PlanService{}).EditTargets(userID.(uint64), req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, "Exercise targets updated successfully")
+}
+
+func UpdateRestDayType(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	dayNumber, err := strconv.Atoi(c.Param("dayNumber"))
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid day number", "Day number must be a valid number")
+		return
+	}
+
+	var req dto.SetDayTypeRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	day, err := (&services.PlanService{}).SetRestDayType(userID.(uint64), dayNumber, req.DayType)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Rest day updated successfully", day)
+}
+
 func GetWorkoutToday(c *gin.Context) {
 	userID, exists := c.Get("userID")
 	if !exists {
@@ -204,23 +294,224 @@ func GetWorkoutToday(c *gin.Context) {
 		return
 	}
 
+	planService := &services.PlanService{}
+
+	// dayID is still accepted for older clients that pick the weekday themselves
 	dayIDStr := c.Query("dayID")
-	if dayIDStr == "" {
-		helpers.ValidationErrorResponse(c, "dayID is required", "")
+	if dayIDStr != "" {
+		dayID, err := strconv.Atoi(dayIDStr)
+		if err != nil {
+			helpers.ValidationErrorResponse(c, "Invalid dayID format", err.Error())
+			return
+		}
+
+		plan, err := planService.GetWorkoutByDayNumber(userID.(uint64), dayID)
+		if err != nil {
+			helpers.ErrorResponse(c, err)
+			return
+		}
+
+		helpers.SuccessResponseWithData(c, "Workout for today fetched successfully", plan)
+		return
+	}
+
+	plan, err := planService.GetWorkoutToday(userID.(uint64))
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Workout for today fetched successfully", plan)
+}
+
+func GetPlanSchedule(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
 		return
 	}
 
-	dayID, err := strconv.ParseUint(dayIDStr, 10, 64)
+	schedule, err := (&services.PlanService{}).GetSchedule(userID.(uint64), c.Query("from"), c.Query("to"))
 	if err != nil {
-		helpers.ValidationErrorResponse(c, "Invalid dayID format", err.Error())
+		helpers.ErrorResponse(c, err)
 		return
 	}
 
-	plan, err := (&services.PlanService{}).GetWorkoutToday(userID.(uint64), dayID)
+	helpers.SuccessResponseWithData(c, "Workout schedule retrieved successfully", schedule)
+}
+
+func GetWorkoutByDate(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	date := c.Query("date")
+	if date == "" {
+		helpers.ValidationErrorResponse(c, "date is required", "")
+		return
+	}
+
+	plan, err := (&services.PlanService{}).GetWorkoutByDate(userID.(uint64), date)
 	if err != nil {
 		helpers.ErrorResponse(c, err)
 		return
 	}
 
-	helpers.SuccessResponseWithData(c, "Workout for today fetched successfully", plan)
+	helpers.SuccessResponseWithData(c, "Workout for the day fetched successfully", plan)
+}
+
+func GetProgressionProposals(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	proposals, err := (&services.ProgressionService{}).GetProposals(userID.(uint64))
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Progression proposals retrieved successfully", proposals)
+}
+
+func ApplyProgression(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	var req dto.ApplyProgressionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	result, err := (&services.ProgressionService{}).ApplyProposals(userID.(uint64), req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Progression applied successfully", result)
+}
+
+func GetPlanHistory(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	history, err := (&services.PlanService{}).GetPlanHistory(userID.(uint64))
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Workout plan history retrieved successfully", history)
+}
+
+func GetPlanByID(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	planID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	weeks, ok := parseScheduleWeeks(c)
+	if !ok {
+		return
+	}
+
+	plan, err := (&services.PlanService{}).GetPlanByID(userID.(uint64), planID, weeks)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Workout plan retrieved successfully", plan)
+}
+
+func ActivatePlan(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	planID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	if err := (&services.PlanService{}).ActivatePlan(userID.(uint64), planID); err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, "Workout plan activated successfully")
+}
+
+func PreviewPlanRegeneration(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	diff, err := (&services.PlanService{}).PreviewRegeneration(userID.(uint64))
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Plan preview generated", diff)
+}
+
+func ApplyPlanDraft(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	draftID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	if err := (&services.PlanService{}).ApplyDraft(userID.(uint64), draftID); err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, "Plan draft applied successfully")
+}
+
+// parseScheduleWeeks reads the optional weeks query parameter, writing a validation error when it is invalid.
+func parseScheduleWeeks(c *gin.Context) (int, bool) {
+	weeksStr := c.Query("weeks")
+	if weeksStr == "" {
+		return helpers.DefaultScheduleWeeks, true
+	}
+
+	weeks, err := strconv.Atoi(weeksStr)
+	if err != nil || weeks < 1 || weeks > helpers.MaxScheduleWeeks {
+		helpers.ValidationErrorResponse(c, "Invalid weeks", fmt.Sprintf("weeks must be between 1 and %d", helpers.MaxScheduleWeeks))
+		return 0, false
+	}
+	return weeks, true
 }
@@ -39,12 +39,13 @@ func UpdateProfile(c *gin.Context) {
 		return
 	}
 
-	if err := (&services.ProfileService{}).UpdateProfile(userID.(uint64), request); err != nil {
+	response, err := (&services.ProfileService{}).UpdateProfile(userID.(uint64), request)
+	if err != nil {
 		helpers.ErrorResponse(c, err)
 		return
 	}
 
-	helpers.SuccessResponse(c, "profile updated successfully")
+	helpers.SuccessResponseWithData(c, "profile updated successfully", response)
 }
 
 func DeleteProfile(c *gin.Context) {
@@ -67,3 +68,13 @@ func DeleteProfile(c *gin.Context) {
 
 	helpers.SuccessResponse(c, "Profile deleted successfully")
 }
+
+func GetLimitationOptions(c *gin.Context) {
+	options, err := (&services.ProfileService{}).GetLimitationOptions()
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "limitation options retrieved successfully", options)
+}
@@ -0,0 +1,139 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func StartSession(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	var req dto.StartSessionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	session, err := (&services.SessionService{}).StartSession(userID.(uint64), req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Workout session started successfully", session)
+}
+
+func GetSessions(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	var planID uint64
+	if planIDStr := c.Query("planId"); planIDStr != "" {
+		parsed, err := strconv.ParseUint(planIDStr, 10, 64)
+		if err != nil {
+			helpers.ValidationErrorResponse(c, "Invalid planId format", err.Error())
+			return
+		}
+		planID = parsed
+	}
+
+	sessions, err := (&services.SessionService{}).GetSessions(userID.(uint64), planID)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Workout sessions retrieved successfully", sessions)
+}
+
+func GetSessionByID(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	session, err := (&services.SessionService{}).GetSessionByID(userID.(uint64), sessionID)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Workout session retrieved successfully", session)
+}
+
+func LogSessionSet(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	var req dto.LogSetRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request body", err.Error())
+		return
+	}
+
+	set, err := (&services.SessionService{}).LogSet(userID.(uint64), sessionID, req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Set logged successfully", set)
+}
+
+func FinishSession(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	sessionID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	var req dto.FinishSessionRequest
+	if c.Request.ContentLength > 0 {
+		if err := c.ShouldBindJSON(&req); err != nil {
+			helpers.ValidationErrorResponse(c, "Invalid request body", err.Error())
+			return
+		}
+	}
+
+	session, err := (&services.SessionService{}).FinishSession(userID.(uint64), sessionID, req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Workout session finished successfully", session)
+}
@@ -0,0 +1,161 @@
+package controllers
+
+import (
+	"errors"
+	"strconv"
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetSplits(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	result, err := (&services.SplitService{}).GetAvailableSplits(userID.(uint64))
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Splits retrieved successfully", result)
+}
+
+func CreateSplit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	var req dto.SplitDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := (&services.SplitService{}).CreateSplit(userID.(uint64), req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Split created successfully", result)
+}
+
+func UpdateSplit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	splitID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	var req dto.SplitDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := (&services.SplitService{}).UpdateSplit(userID.(uint64), splitID, req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Split updated successfully", result)
+}
+
+func DeleteSplit(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	splitID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	if err := (&services.SplitService{}).DeleteSplit(userID.(uint64), splitID); err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, "Split deleted successfully")
+}
+
+func GetGlobalSplits(c *gin.Context) {
+	result, err := (&services.SplitService{}).GetGlobalSplits()
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Global splits retrieved successfully", result)
+}
+
+func CreateGlobalSplit(c *gin.Context) {
+	var req dto.SplitDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := (&services.SplitService{}).CreateGlobalSplit(req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Global split created successfully", result)
+}
+
+func UpdateGlobalSplit(c *gin.Context) {
+	splitID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	var req dto.SplitDefinitionRequest
+	if err := c.ShouldBindJSON(&req); err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid request", err.Error())
+		return
+	}
+
+	result, err := (&services.SplitService{}).UpdateGlobalSplit(splitID, req)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Global split updated successfully", result)
+}
+
+func DeleteGlobalSplit(c *gin.Context) {
+	splitID, err := strconv.ParseUint(c.Param("id"), 10, 64)
+	if err != nil {
+		helpers.ValidationErrorResponse(c, "Invalid ID", "ID must be a valid number")
+		return
+	}
+
+	if err := (&services.SplitService{}).DeleteGlobalSplit(splitID); err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponse(c, "Global split deleted successfully")
+}
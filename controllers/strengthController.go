@@ -0,0 +1,35 @@
+package controllers
+
+import (
+	"errors"
+	"strings"
+	"wellnesspath/helpers"
+	"wellnesspath/services"
+
+	"github.com/gin-gonic/gin"
+)
+
+func GetStrengthProfile(c *gin.Context) {
+	userID, exists := c.Get("userID")
+	if !exists {
+		helpers.ErrorResponse(c, errors.New("user not authenticated"))
+		return
+	}
+
+	formula := helpers.DefaultOneRMFormula
+	if formulaStr := c.Query("formula"); formulaStr != "" {
+		formula = strings.ToLower(formulaStr)
+		if !helpers.IsValidOneRMFormula(formula) {
+			helpers.ValidationErrorResponse(c, "Invalid formula", "formula must be epley or brzycki")
+			return
+		}
+	}
+
+	result, err := (&services.StrengthService{}).GetStrengthProfile(userID.(uint64), formula)
+	if err != nil {
+		helpers.ErrorResponse(c, err)
+		return
+	}
+
+	helpers.SuccessResponseWithData(c, "Strength profile retrieved successfully", result)
+}
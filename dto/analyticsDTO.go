@@ -0,0 +1,30 @@
+package dto
+
+type VolumeAnalyticsOutput struct {
+	PlanID uint64          `json:"planId"`
+	Weeks  []WeeklyVolume  `json:"weeks"`
+	Trends []BodyPartTrend `json:"trends"`
+}
+
+type WeeklyVolume struct {
+	WeekStart string           `json:"weekStart"`
+	WeekEnd   string           `json:"weekEnd"`
+	BodyParts []BodyPartVolume `json:"bodyParts"`
+}
+
+type BodyPartVolume struct {
+	BodyPart          string  `json:"bodyPart"`
+	PrescribedSets    int     `json:"prescribedSets"`
+	PrescribedTonnage float64 `json:"prescribedTonnage"`
+	PerformedSets     int     `json:"performedSets"`
+	PerformedTonnage  float64 `json:"performedTonnage"`
+}
+
+type BodyPartTrend struct {
+	BodyPart             string    `json:"bodyPart"`
+	PrescribedSets       []int     `json:"prescribedSets"`
+	PerformedSets        []int     `json:"performedSets"`
+	PerformedTonnage     []float64 `json:"performedTonnage"`
+	TonnageChangePercent float64   `json:"tonnageChangePercent"`
+	Trend                string    `json:"trend"`
+}
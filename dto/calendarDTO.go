@@ -0,0 +1,7 @@
+package dto
+
+type CalendarFeedResponse struct {
+	Token    string `json:"token"`
+	FeedPath string `json:"feedPath"`
+	FeedURL  string `json:"feedUrl"`
+}
@@ -17,3 +17,24 @@ type VideoResponseDTO struct {
 	ExerciseID uint64 `json:"exercise_id"`
 	VideoURL   string `json:"video_url"`
 }
+
+type ExercisePreferenceRequest struct {
+	Preference string `json:"preference" binding:"required,oneof=favourite blocked"`
+}
+
+type ExercisePreferenceItem struct {
+	ExerciseID uint64 `json:"exercise_id"`
+	Name       string `json:"name"`
+	BodyPart   string `json:"body_part"`
+}
+
+type ExercisePreferencesResponseDTO struct {
+	Favourites []ExercisePreferenceItem `json:"favourites"`
+	Blocked    []ExercisePreferenceItem `json:"blocked"`
+}
+
+type SetExercisePreferenceResponseDTO struct {
+	ExerciseID   uint64 `json:"exercise_id"`
+	Preference   string `json:"preference"`
+	InActivePlan bool   `json:"in_active_plan"`
+}
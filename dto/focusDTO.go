@@ -0,0 +1,12 @@
+package dto
+
+type FocusMappingRequest struct {
+	Focus     string   `json:"focus" binding:"required,max=50"`
+	BodyParts []string `json:"bodyParts" binding:"required,min=1"`
+}
+
+type FocusMappingResponse struct {
+	ID        uint64   `json:"id"`
+	Focus     string   `json:"focus"`
+	BodyParts []string `json:"bodyParts"`
+}
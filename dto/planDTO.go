@@ -1,55 +1,236 @@
 package dto
 
-import "wellnesspath/models"
+import (
+	"time"
+	"wellnesspath/models"
+)
 
 type FullPlanOutput struct {
+	PlanID         uint64                       `json:"planId"`
+	Version        int                          `json:"version"`
+	Seed           int64                        `json:"seed"`
+	IsActive       bool                         `json:"isActive"`
+	StartDate      string                       `json:"startDate"`
+	Mesocycle      []PlanWeek                   `json:"mesocycle,omitempty"`
+	CurrentWeek    *PlanWeek                    `json:"currentWeek,omitempty"`
 	WorkoutPlan    []WorkoutDay                 `json:"workoutPlan"`
 	Schedule       []ScheduledExercise          `json:"schedule,omitempty"`
+	WeeklyVolume   []MuscleVolume               `json:"weeklyVolume"`
 	BMIInfo        BMIInfo                      `json:"bmiInfo"`
 	CaloriesBurned CaloriesBurned               `json:"caloriesBurned"`
 	NutritionPlan  DailyNutritionRecommendation `json:"nutritionPlan"`
 	TrainingAdvice string                       `json:"trainingAdvice"`
 }
 
+type GeneratePlanOptions struct {
+	Seed           *int64 `form:"seed" binding:"omitempty,min=1,max=2147483647"`
+	MesocycleWeeks int    `form:"mesocycleWeeks" binding:"omitempty,min=3,max=8"`
+}
+
+// PlanWeek is one week of a plan's mesocycle; StartDate is set when the week is resolved for a date.
+type PlanWeek struct {
+	WeekNumber int    `json:"weekNumber"`
+	Phase      string `json:"phase"`
+	StartDate  string `json:"startDate,omitempty"`
+}
+
+type GeneratePlanOutput struct {
+	PlanID  uint64 `json:"planId"`
+	Version int    `json:"version"`
+	Seed    int64  `json:"seed"`
+}
+
+type PlanVersionSummary struct {
+	PlanID       uint64    `json:"planId"`
+	Version      int       `json:"version"`
+	Seed         int64     `json:"seed"`
+	SplitType    string    `json:"splitType"`
+	Goal         string    `json:"goal"`
+	StartDate    string    `json:"startDate"`
+	IsActive     bool      `json:"isActive"`
+	SessionCount int       `json:"sessionCount"`
+	CreatedAt    time.Time `json:"createdAt"`
+}
+
 type WorkoutDay struct {
+	DayID     uint64                 `json:"dayId"`
 	DayNumber int                    `json:"dayNumber"`
+	Weekday   string                 `json:"weekday"`
+	DayType   string                 `json:"dayType"`
 	Focus     string                 `json:"focus"`
+	BodyParts []string               `json:"bodyParts,omitempty"`
+	Warmup    []BlockItem            `json:"warmup,omitempty"`
 	Exercises []ExercisePlanResponse `json:"exercises"`
+	Groups    []ExerciseGroup        `json:"groups,omitempty"`
+	Cooldown  []BlockItem            `json:"cooldown,omitempty"`
+	Recovery  []BlockItem            `json:"recovery,omitempty"`
+
+	EstimatedDurationMinutes int `json:"estimatedDurationMinutes"`
+}
+
+// ExerciseGroup lists plan exercises performed back to back as one round, repeated Rounds times.
+type ExerciseGroup struct {
+	GroupNumber              int      `json:"groupNumber"`
+	Type                     string   `json:"type"`
+	Rounds                   int      `json:"rounds"`
+	RestBetweenRoundsSeconds int      `json:"restBetweenRoundsSeconds"`
+	PlanExerciseIDs          []uint64 `json:"planExerciseIds"`
 }
 
 type WorkoutDayToday struct {
+	DayID     uint64                  `json:"dayId"`
 	DayNumber int                     `json:"dayNumber"`
+	Weekday   string                  `json:"weekday"`
+	Date      string                  `json:"date,omitempty"`
+	DayType   string                  `json:"dayType"`
 	Focus     string                  `json:"focus"`
+	Week      *PlanWeek               `json:"week,omitempty"`
+	Warmup    []BlockItem             `json:"warmup,omitempty"`
 	Exercises []ExerciseTodayResponse `json:"exercises"`
+	Groups    []ExerciseGroup         `json:"groups,omitempty"`
+	Cooldown  []BlockItem             `json:"cooldown,omitempty"`
+	Recovery  []BlockItem             `json:"recovery,omitempty"`
+
+	EstimatedDurationMinutes int `json:"estimatedDurationMinutes"`
+}
+
+// BlockItem is one part of a day's warm-up, cool-down or rest-day recovery: light cardio, a
+// mobility drill, a stretch, or a ramp-up set of the first compound lift at PercentOfWorkingLoad
+// of its working load. Optional items can be skipped.
+type BlockItem struct {
+	Block                string  `json:"block"`
+	Type                 string  `json:"type"`
+	Name                 string  `json:"name"`
+	Optional             bool    `json:"optional,omitempty"`
+	PlanExerciseID       uint64  `json:"planExerciseId,omitempty"`
+	ExerciseID           uint64  `json:"exerciseId,omitempty"`
+	Sets                 int     `json:"sets,omitempty"`
+	Reps                 int     `json:"reps,omitempty"`
+	Load                 float64 `json:"load,omitempty"`
+	PercentOfWorkingLoad int     `json:"percentOfWorkingLoad,omitempty"`
+	DurationSeconds      int     `json:"durationSeconds"`
 }
 
 type ExercisePlanResponse struct {
-	ExerciseID uint64 `json:"exerciseId"`
-	Name       string `json:"name"`
-	Reps       int    `json:"reps"`
-	Sets       int    `json:"sets"`
-	Order      int    `json:"order"`
-	Note       string `json:"note,omitempty"`
-	BodyPart   string `json:"body_part"`
-	Equipment  string `json:"equipment"`
+	PlanExerciseID  uint64           `json:"planExerciseId"`
+	ExerciseID      uint64           `json:"exerciseId"`
+	Name            string           `json:"name"`
+	Block           string           `json:"block,omitempty"`
+	Mode            string           `json:"mode,omitempty"`
+	Reps            int              `json:"reps"`
+	Sets            int              `json:"sets"`
+	Load            float64          `json:"load"`
+	RestSeconds     int              `json:"restSeconds"`
+	Tempo           string           `json:"tempo,omitempty"`
+	TargetRPE       float64          `json:"targetRpe,omitempty"`
+	TargetRIR       int              `json:"targetRir"`
+	DurationSeconds int              `json:"durationSeconds,omitempty"`
+	DistanceMeters  int              `json:"distanceMeters,omitempty"`
+	TargetPace      string           `json:"targetPace,omitempty"`
+	HeartRateZone   int              `json:"heartRateZone,omitempty"`
+	WorkSeconds     int              `json:"workSeconds,omitempty"`
+	Order           int              `json:"order"`
+	GroupNumber     int              `json:"groupNumber,omitempty"`
+	Note            string           `json:"note,omitempty"`
+	Base            *PrescriptionDTO `json:"base,omitempty"`
+	BodyPart        string           `json:"body_part"`
+	Equipment       string           `json:"equipment"`
+	SelectionReason *SelectionReason `json:"selectionReason,omitempty"`
+}
+
+type SelectionReason struct {
+	Tier             string          `json:"tier"`
+	MatchedGoalTag   string          `json:"matchedGoalTag,omitempty"`
+	Difficulty       string          `json:"difficulty"`
+	DifficultyMatch  bool            `json:"difficultyMatch"`
+	BodyPart         string          `json:"bodyPart"`
+	BodyPartCoverage string          `json:"bodyPartCoverage,omitempty"`
+	FallbackUsed     bool            `json:"fallbackUsed"`
+	Favourite        bool            `json:"favourite,omitempty"`
+	Score            float64         `json:"score,omitempty"`
+	ScoreBreakdown   *ScoreBreakdown `json:"scoreBreakdown,omitempty"`
+	Summary          string          `json:"summary"`
+}
+
+// ScoreBreakdown holds each weighted criterion of an exercise's selection score.
+type ScoreBreakdown struct {
+	GoalTag    float64 `json:"goalTag"`
+	Difficulty float64 `json:"difficulty"`
+	Equipment  float64 `json:"equipment"`
+	Compound   float64 `json:"compound"`
+	Rating     float64 `json:"rating"`
+	Coverage   float64 `json:"coverage"`
+	Volume     float64 `json:"volume"`
+	Favourite  float64 `json:"favourite,omitempty"`
+	Limitation float64 `json:"limitation,omitempty"`
+}
+
+func (b ScoreBreakdown) Total() float64 {
+	return b.GoalTag + b.Difficulty + b.Equipment + b.Compound + b.Rating + b.Coverage + b.Volume + b.Favourite + b.Limitation
+}
+
+type MuscleVolume struct {
+	BodyPart  string `json:"bodyPart"`
+	Sets      int    `json:"sets"`
+	Exercises int    `json:"exercises"`
+	TargetMin int    `json:"targetMin"`
+	TargetMax int    `json:"targetMax"`
+	Status    string `json:"status"`
 }
 
 type ExerciseTodayResponse struct {
-	ExerciseID uint64 `json:"exerciseId"`
-	Name       string `json:"name"`
-	Reps       int    `json:"reps"`
-	Sets       int    `json:"sets"`
-	Order      int    `json:"order"`
-	Note       string `json:"note,omitempty"`
-	ImageURL   string `json:"image_url"`
+	PlanExerciseID  uint64           `json:"planExerciseId"`
+	ExerciseID      uint64           `json:"exerciseId"`
+	Name            string           `json:"name"`
+	Block           string           `json:"block,omitempty"`
+	Mode            string           `json:"mode,omitempty"`
+	Reps            int              `json:"reps"`
+	Sets            int              `json:"sets"`
+	Load            float64          `json:"load"`
+	RestSeconds     int              `json:"restSeconds"`
+	Tempo           string           `json:"tempo,omitempty"`
+	TargetRPE       float64          `json:"targetRpe,omitempty"`
+	TargetRIR       int              `json:"targetRir"`
+	DurationSeconds int              `json:"durationSeconds,omitempty"`
+	DistanceMeters  int              `json:"distanceMeters,omitempty"`
+	TargetPace      string           `json:"targetPace,omitempty"`
+	HeartRateZone   int              `json:"heartRateZone,omitempty"`
+	WorkSeconds     int              `json:"workSeconds,omitempty"`
+	Order           int              `json:"order"`
+	GroupNumber     int              `json:"groupNumber,omitempty"`
+	Note            string           `json:"note,omitempty"`
+	Base            *PrescriptionDTO `json:"base,omitempty"`
+	ImageURL        string           `json:"image_url"`
 }
 
 type ScheduledExercise struct {
-	DayNumber int    `json:"dayNumber"`
-	Date      string `json:"date"`
-	Exercise  string `json:"exercise"`
-	Reps      int    `json:"reps"`
-	Sets      int    `json:"sets"`
+	DayNumber       int     `json:"dayNumber"`
+	Date            string  `json:"date"`
+	Focus           string  `json:"focus"`
+	WeekNumber      int     `json:"weekNumber,omitempty"`
+	Phase           string  `json:"phase,omitempty"`
+	PlanExerciseID  uint64  `json:"planExerciseId"`
+	ExerciseID      uint64  `json:"exerciseId"`
+	Exercise        string  `json:"exercise"`
+	Mode            string  `json:"mode,omitempty"`
+	Reps            int     `json:"reps"`
+	Sets            int     `json:"sets"`
+	Load            float64 `json:"load"`
+	RestSeconds     int     `json:"restSeconds"`
+	Tempo           string  `json:"tempo,omitempty"`
+	TargetRPE       float64 `json:"targetRpe,omitempty"`
+	TargetRIR       int     `json:"targetRir"`
+	DurationSeconds int     `json:"durationSeconds,omitempty"`
+	DistanceMeters  int     `json:"distanceMeters,omitempty"`
+	TargetPace      string  `json:"targetPace,omitempty"`
+	HeartRateZone   int     `json:"heartRateZone,omitempty"`
+	WorkSeconds     int     `json:"workSeconds,omitempty"`
+}
+
+type ScheduleOutput struct {
+	From     string              `json:"from"`
+	To       string              `json:"to"`
+	Schedule []ScheduledExercise `json:"schedule"`
 }
 
 type BMIInfo struct {
@@ -92,6 +273,7 @@ type RecommendedExerciseBrief struct {
 type ReplaceExerciseRequest struct {
 	OriginalExerciseID uint64 `json:"originalExerciseID" binding:"required"`
 	NewExerciseID      uint64 `json:"newExerciseID" binding:"required"`
+	BlockOriginal      bool   `json:"blockOriginal"`
 }
 
 type EditRepsRequest struct {
@@ -99,6 +281,19 @@ type EditRepsRequest struct {
 	NewReps        int    `json:"newReps" binding:"required,min=1,max=100"`
 }
 
+type SetDayTypeRequest struct {
+	DayType string `json:"dayType" binding:"required,oneof=rest active_recovery"`
+}
+
+// EditTargetsRequest changes the rest, tempo or effort targets of a plan exercise; omitted fields are kept.
+type EditTargetsRequest struct {
+	PlanExerciseID uint64   `json:"planExerciseId" binding:"required"`
+	RestSeconds    *int     `json:"restSeconds" binding:"omitempty,min=0,max=600"`
+	Tempo          *string  `json:"tempo" binding:"omitempty,max=15"`
+	TargetRPE      *float64 `json:"targetRpe" binding:"omitempty,min=5,max=10"`
+	TargetRIR      *int     `json:"targetRir" binding:"omitempty,min=0,max=5"`
+}
+
 type WorkoutDayOutput struct {
 	DayNumber int                     `json:"dayNumber"`
 	Focus     string                  `json:"focus"`
@@ -119,11 +314,13 @@ type InitializePlanOutput struct {
 
 type CreateDaysRequest struct {
 	PlanID   uint64         `json:"plan_id"`
+	Seed     int64          `json:"seed"`
 	RestDays []int          `json:"rest_days"`
 	Profile  models.Profile `json:"profile"`
 }
 
 type InsertExercisesRequest struct {
+	Seed    int64                   `json:"seed"`
 	Profile models.Profile          `json:"profile"`
 	Days    []models.WorkoutPlanDay `json:"days"`
 }
@@ -0,0 +1,59 @@
+package dto
+
+const (
+	ExerciseChangeAdded        = "added"
+	ExerciseChangeRemoved      = "removed"
+	ExerciseChangeSwapped      = "swapped"
+	ExerciseChangePrescription = "prescription_changed"
+)
+
+type PlanDiff struct {
+	BasePlanID              uint64           `json:"basePlanId"`
+	DraftPlanID             uint64           `json:"draftPlanId"`
+	HasChanges              bool             `json:"hasChanges"`
+	ProfileChangedSincePlan bool             `json:"profileChangedSincePlan"`
+	DaysAdded               []DayChange      `json:"daysAdded"`
+	DaysRemoved             []DayChange      `json:"daysRemoved"`
+	FocusChanges            []FocusChange    `json:"focusChanges"`
+	ExerciseChanges         []ExerciseChange `json:"exerciseChanges"`
+}
+
+type DayChange struct {
+	DayNumber int                `json:"dayNumber"`
+	Weekday   string             `json:"weekday"`
+	Focus     string             `json:"focus"`
+	Exercises []ExerciseSnapshot `json:"exercises,omitempty"`
+}
+
+type FocusChange struct {
+	DayNumber int    `json:"dayNumber"`
+	Weekday   string `json:"weekday"`
+	From      string `json:"from"`
+	To        string `json:"to"`
+}
+
+type ExerciseChange struct {
+	DayNumber int               `json:"dayNumber"`
+	Type      string            `json:"type"`
+	Before    *ExerciseSnapshot `json:"before,omitempty"`
+	After     *ExerciseSnapshot `json:"after,omitempty"`
+}
+
+type ExerciseSnapshot struct {
+	ExerciseID      uint64   `json:"exerciseId"`
+	Name            string   `json:"name"`
+	Mode            string   `json:"mode"`
+	Reps            int      `json:"reps"`
+	Sets            int      `json:"sets"`
+	Load            float64  `json:"load"`
+	RestSeconds     int      `json:"restSeconds"`
+	Tempo           string   `json:"tempo,omitempty"`
+	TargetRPE       float64  `json:"targetRpe,omitempty"`
+	TargetRIR       int      `json:"targetRir"`
+	DurationSeconds int      `json:"durationSeconds,omitempty"`
+	DistanceMeters  int      `json:"distanceMeters,omitempty"`
+	WorkSeconds     int      `json:"workSeconds,omitempty"`
+	HeartRateZone   int      `json:"heartRateZone,omitempty"`
+	GroupType       string   `json:"groupType,omitempty"`
+	GroupedWith     []uint64 `json:"groupedWith,omitempty"`
+}
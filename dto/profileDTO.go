@@ -1,27 +1,50 @@
 package dto
 
 type UpdateProfileDTO struct {
-	SplitType          string   `json:"split_type"`
-	Intensity          string   `json:"intensity"`
-	TargetWeight       float64  `json:"target_weight"`
-	BMI                float64  `json:"bmi"`
-	BMICategory        string   `json:"bmi_category"`
-	DurationPerSession int      `json:"duration_per_session"`
-	Goal               string   `json:"goal"`
-	Equipment          []string `json:"equipment"`
-	RestDays           []int    `json:"rest_days"`
+	SplitType          string       `json:"split_type"`
+	Intensity          string       `json:"intensity"`
+	TargetWeight       float64      `json:"target_weight"`
+	BodyWeight         float64      `json:"body_weight" binding:"omitempty,min=0,max=500"`
+	BMI                float64      `json:"bmi"`
+	BMICategory        string       `json:"bmi_category"`
+	DurationPerSession int          `json:"duration_per_session"`
+	Goal               string       `json:"goal"`
+	Equipment          []string     `json:"equipment"`
+	RestDays           []int        `json:"rest_days"`
+	Timezone           string       `json:"timezone"`
+	Limitations        []Limitation `json:"limitations" binding:"omitempty,max=20,dive"`
+}
+
+// Limitation is an injury or contraindication: a body part or movement pattern to avoid or limit.
+type Limitation struct {
+	Type     string `json:"type" binding:"required,oneof=body_part movement"`
+	Target   string `json:"target" binding:"required,max=50"`
+	Severity string `json:"severity" binding:"required,oneof=avoid limit"`
+}
+
+type UpdateProfileResponseDTO struct {
+	PlanOutdated bool `json:"plan_outdated"`
 }
 
 type ProfileResponseDTO struct {
-	ID                 uint64   `json:"id"`
-	SplitType          string   `json:"split_type"`
-	Intensity          string   `json:"intensity"`
-	TargetWeight       float64  `json:"target_weight"`
-	BMI                float64  `json:"bmi"`
-	BMICategory        string   `json:"bmi_category"`
-	Frequency          int      `json:"frequency"`
-	DurationPerSession int      `json:"duration_per_session"`
-	Goal               string   `json:"goal"`
-	Equipment          []string `json:"equipment"`
-	RestDays           []int    `json:"rest_days"`
+	ID                 uint64       `json:"id"`
+	SplitType          string       `json:"split_type"`
+	Intensity          string       `json:"intensity"`
+	TargetWeight       float64      `json:"target_weight"`
+	BodyWeight         float64      `json:"body_weight"`
+	BMI                float64      `json:"bmi"`
+	BMICategory        string       `json:"bmi_category"`
+	Frequency          int          `json:"frequency"`
+	DurationPerSession int          `json:"duration_per_session"`
+	Goal               string       `json:"goal"`
+	Equipment          []string     `json:"equipment"`
+	RestDays           []int        `json:"rest_days"`
+	Timezone           string       `json:"timezone"`
+	Limitations        []Limitation `json:"limitations"`
+}
+
+type LimitationOptionsDTO struct {
+	BodyParts        []string `json:"body_parts"`
+	MovementPatterns []string `json:"movement_patterns"`
+	Severities       []string `json:"severities"`
 }
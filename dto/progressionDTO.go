@@ -0,0 +1,28 @@
+package dto
+
+type PrescriptionDTO struct {
+	Reps int     `json:"reps"`
+	Sets int     `json:"sets"`
+	Load float64 `json:"load"`
+}
+
+type ProgressionProposal struct {
+	PlanExerciseID uint64          `json:"planExerciseId"`
+	ExerciseID     uint64          `json:"exerciseId"`
+	Name           string          `json:"name"`
+	DayNumber      int             `json:"dayNumber"`
+	Rule           string          `json:"rule"`
+	Reason         string          `json:"reason"`
+	Current        PrescriptionDTO `json:"current"`
+	Proposed       PrescriptionDTO `json:"proposed"`
+}
+
+type ApplyProgressionRequest struct {
+	Accept []uint64 `json:"accept"`
+	Reject []uint64 `json:"reject"`
+}
+
+type ApplyProgressionResponse struct {
+	Applied  []ProgressionProposal `json:"applied"`
+	Rejected []uint64              `json:"rejected"`
+}
@@ -0,0 +1,48 @@
+package dto
+
+import "time"
+
+type StartSessionRequest struct {
+	DayID uint64 `json:"dayId" binding:"required"`
+	Note  string `json:"note"`
+}
+
+type LogSetRequest struct {
+	PlanExerciseID uint64     `json:"planExerciseId" binding:"required"`
+	SetNumber      int        `json:"setNumber" binding:"omitempty,min=1"`
+	Reps           int        `json:"reps" binding:"min=0,max=100"`
+	Load           float64    `json:"load" binding:"min=0"`
+	RPE            float64    `json:"rpe" binding:"omitempty,min=1,max=10"`
+	CompletedAt    *time.Time `json:"completedAt"`
+}
+
+type FinishSessionRequest struct {
+	Note string `json:"note"`
+}
+
+type SessionResponse struct {
+	ID          uint64               `json:"id"`
+	PlanID      uint64               `json:"planId"`
+	PlanVersion int                  `json:"planVersion"`
+	DayID       uint64               `json:"dayId"`
+	DayNumber   int                  `json:"dayNumber"`
+	Focus       string               `json:"focus"`
+	Status      string               `json:"status"`
+	StartedAt   time.Time            `json:"startedAt"`
+	FinishedAt  *time.Time           `json:"finishedAt,omitempty"`
+	Note        string               `json:"note,omitempty"`
+	Sets        []SessionSetResponse `json:"sets"`
+}
+
+type SessionSetResponse struct {
+	ID             uint64    `json:"id"`
+	PlanExerciseID uint64    `json:"planExerciseId"`
+	ExerciseID     uint64    `json:"exerciseId"`
+	Name           string    `json:"name"`
+	SetNumber      int       `json:"setNumber"`
+	Reps           int       `json:"reps"`
+	Load           float64   `json:"load"`
+	RPE            float64   `json:"rpe,omitempty"`
+	PersonalRecord bool      `json:"personalRecord,omitempty"`
+	CompletedAt    time.Time `json:"completedAt"`
+}
@@ -0,0 +1,32 @@
+package dto
+
+type SplitFocusRequest struct {
+	Name      string   `json:"name" binding:"required,max=50"`
+	BodyParts []string `json:"bodyParts" binding:"required,min=1"`
+}
+
+type SplitDefinitionRequest struct {
+	Name           string              `json:"name" binding:"required,max=50"`
+	Description    string              `json:"description"`
+	Focuses        []SplitFocusRequest `json:"focuses" binding:"required,min=1,max=7,dive"`
+	MinFrequency   int                 `json:"minFrequency" binding:"omitempty,min=1,max=7"`
+	MaxFrequency   int                 `json:"maxFrequency" binding:"omitempty,min=1,max=7"`
+	FullCyclesOnly bool                `json:"fullCyclesOnly"`
+}
+
+type SplitDefinitionResponse struct {
+	ID             uint64               `json:"id,omitempty"`
+	Name           string               `json:"name"`
+	Description    string               `json:"description,omitempty"`
+	Scope          string               `json:"scope"`
+	MinFrequency   int                  `json:"minFrequency"`
+	MaxFrequency   int                  `json:"maxFrequency"`
+	FullCyclesOnly bool                 `json:"fullCyclesOnly"`
+	Focuses        []SplitFocusResponse `json:"focuses"`
+}
+
+type SplitFocusResponse struct {
+	Order     int      `json:"order"`
+	Name      string   `json:"name"`
+	BodyParts []string `json:"bodyParts"`
+}
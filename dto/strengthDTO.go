@@ -0,0 +1,56 @@
+package dto
+
+import "time"
+
+type StrengthProfileOutput struct {
+	Formula         string                   `json:"formula"`
+	BodyWeight      float64                  `json:"bodyWeight"`
+	Intensity       string                   `json:"intensity"`
+	Exercises       []ExerciseStrength       `json:"exercises"`
+	Standards       []LiftStandard           `json:"standards"`
+	PersonalRecords []PersonalRecordResponse `json:"personalRecords"`
+	Suggestion      *IntensitySuggestion     `json:"suggestion,omitempty"`
+	Notice          string                   `json:"notice,omitempty"`
+}
+
+// ExerciseStrength is the best estimated one-rep max logged for an exercise and the set it came from.
+type ExerciseStrength struct {
+	ExerciseID       uint64    `json:"exerciseId"`
+	Name             string    `json:"name"`
+	EstimatedOneRM   float64   `json:"estimatedOneRm"`
+	Load             float64   `json:"load"`
+	Reps             int       `json:"reps"`
+	RelativeStrength float64   `json:"relativeStrength,omitempty"`
+	AchievedAt       time.Time `json:"achievedAt"`
+}
+
+// LiftStandard compares the best estimated one-rep max of a reference lift with bodyweight multiples per intensity level.
+type LiftStandard struct {
+	Lift             string             `json:"lift"`
+	ExerciseID       uint64             `json:"exerciseId,omitempty"`
+	Name             string             `json:"name,omitempty"`
+	EstimatedOneRM   float64            `json:"estimatedOneRm"`
+	RelativeStrength float64            `json:"relativeStrength"`
+	Level            string             `json:"level,omitempty"`
+	NextLevel        string             `json:"nextLevel,omitempty"`
+	NextLevelOneRM   float64            `json:"nextLevelOneRm,omitempty"`
+	Standards        map[string]float64 `json:"standards"`
+}
+
+type PersonalRecordResponse struct {
+	ID             uint64    `json:"id"`
+	ExerciseID     uint64    `json:"exerciseId"`
+	Name           string    `json:"name"`
+	Load           float64   `json:"load"`
+	Reps           int       `json:"reps"`
+	EstimatedOneRM float64   `json:"estimatedOneRm"`
+	PreviousOneRM  float64   `json:"previousOneRm"`
+	Formula        string    `json:"formula"`
+	AchievedAt     time.Time `json:"achievedAt"`
+}
+
+type IntensitySuggestion struct {
+	CurrentIntensity   string `json:"currentIntensity"`
+	SuggestedIntensity string `json:"suggestedIntensity"`
+	Reason             string `json:"reason"`
+}
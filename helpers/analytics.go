@@ -0,0 +1,175 @@
+package helpers
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"time"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+const (
+	DefaultAnalyticsWeeks = 4
+	MaxAnalyticsWeeks     = 26
+
+	TrendIncreasing = "increasing"
+	TrendDecreasing = "decreasing"
+	TrendSteady     = "steady"
+	TrendNoData     = "no_data"
+)
+
+// trendThresholdPercent is the tonnage change below which a trend counts as steady.
+const trendThresholdPercent = 5
+
+// AnalyticsWeekStarts returns the Mondays of the last weeks weeks, oldest first, ending with the week of today.
+func AnalyticsWeekStarts(today time.Time, weeks int) []time.Time {
+	currentWeek := today.AddDate(0, 0, 1-DayNumberForDate(today))
+	starts := make([]time.Time, weeks)
+	for i := 0; i < weeks; i++ {
+		starts[i] = currentWeek.AddDate(0, 0, -7*(weeks-1-i))
+	}
+	return starts
+}
+
+// AggregateWeeklyVolume buckets prescribed (scheduled) and performed (logged) work per Monday-based
+// week and body part. Performed sets are placed by their completion date in loc.
+func AggregateWeeklyVolume(weekStarts []time.Time, scheduled []dto.ScheduledExercise, performed []models.WorkoutSessionSet, exMap map[uint64]*models.Exercise, loc *time.Location) []dto.WeeklyVolume {
+	type bucket map[string]*dto.BodyPartVolume
+	buckets := make([]bucket, len(weekStarts))
+	for i := range buckets {
+		buckets[i] = bucket{}
+	}
+
+	weekIndex := func(date time.Time) int {
+		for i := len(weekStarts) - 1; i >= 0; i-- {
+			if !date.Before(weekStarts[i]) {
+				if date.Before(weekStarts[i].AddDate(0, 0, 7)) {
+					return i
+				}
+				return -1
+			}
+		}
+		return -1
+	}
+	volumeFor := func(week int, exerciseID uint64) *dto.BodyPartVolume {
+		detail, ok := exMap[exerciseID]
+		if !ok || detail == nil {
+			return nil
+		}
+		key := strings.ToLower(detail.BodyPart)
+		if _, ok := buckets[week][key]; !ok {
+			buckets[week][key] = &dto.BodyPartVolume{BodyPart: detail.BodyPart}
+		}
+		return buckets[week][key]
+	}
+
+	for _, item := range scheduled {
+		date, err := ParseCivilDate(item.Date)
+		if err != nil {
+			continue
+		}
+		week := weekIndex(date)
+		if week < 0 || !IsRepsMode(item.Mode) {
+			continue
+		}
+		if volume := volumeFor(week, item.ExerciseID); volume != nil {
+			volume.PrescribedSets += item.Sets
+			volume.PrescribedTonnage += float64(item.Sets*item.Reps) * item.Load
+		}
+	}
+
+	for _, set := range performed {
+		week := weekIndex(CivilDate(set.CompletedAt.In(loc)))
+		if week < 0 {
+			continue
+		}
+		if volume := volumeFor(week, set.ExerciseID); volume != nil {
+			volume.PerformedSets++
+			volume.PerformedTonnage += float64(set.Reps) * set.Load
+		}
+	}
+
+	result := make([]dto.WeeklyVolume, len(weekStarts))
+	for i, start := range weekStarts {
+		week := dto.WeeklyVolume{
+			WeekStart: start.Format(DateLayout),
+			WeekEnd:   start.AddDate(0, 0, 6).Format(DateLayout),
+			BodyParts: []dto.BodyPartVolume{},
+		}
+		for _, volume := range buckets[i] {
+			volume.PrescribedTonnage = roundTonnage(volume.PrescribedTonnage)
+			volume.PerformedTonnage = roundTonnage(volume.PerformedTonnage)
+			week.BodyParts = append(week.BodyParts, *volume)
+		}
+		sort.Slice(week.BodyParts, func(a, b int) bool { return week.BodyParts[a].BodyPart < week.BodyParts[b].BodyPart })
+		result[i] = week
+	}
+	return result
+}
+
+// BuildVolumeTrends lines up each body part's weekly numbers and compares the performed tonnage
+// of the last complete week with the earliest week in which the body part was trained. The
+// current week is still in progress, so it is listed but left out of the comparison.
+func BuildVolumeTrends(weeks []dto.WeeklyVolume) []dto.BodyPartTrend {
+	trends := map[string]*dto.BodyPartTrend{}
+	var order []string
+
+	for i, week := range weeks {
+		for _, volume := range week.BodyParts {
+			key := strings.ToLower(volume.BodyPart)
+			trend, ok := trends[key]
+			if !ok {
+				trend = &dto.BodyPartTrend{
+					BodyPart:         volume.BodyPart,
+					PrescribedSets:   make([]int, len(weeks)),
+					PerformedSets:    make([]int, len(weeks)),
+					PerformedTonnage: make([]float64, len(weeks)),
+				}
+				trends[key] = trend
+				order = append(order, key)
+			}
+			trend.PrescribedSets[i] = volume.PrescribedSets
+			trend.PerformedSets[i] = volume.PerformedSets
+			trend.PerformedTonnage[i] = volume.PerformedTonnage
+		}
+	}
+
+	sort.Strings(order)
+	result := make([]dto.BodyPartTrend, 0, len(order))
+	for _, key := range order {
+		trend := trends[key]
+		trend.Trend = TrendNoData
+
+		first := -1
+		for i, sets := range trend.PerformedSets {
+			if sets > 0 {
+				first = i
+				break
+			}
+		}
+		last := len(trend.PerformedSets) - 2
+		if first >= 0 && first < last {
+			before, after := trend.PerformedTonnage[first], trend.PerformedTonnage[last]
+			if before > 0 {
+				trend.TonnageChangePercent = math.Round((after-before)/before*1000) / 10
+			}
+			switch {
+			case before == 0 && after > 0:
+				trend.Trend = TrendIncreasing
+			case trend.TonnageChangePercent > trendThresholdPercent:
+				trend.Trend = TrendIncreasing
+			case trend.TonnageChangePercent < -trendThresholdPercent:
+				trend.Trend = TrendDecreasing
+			default:
+				trend.Trend = TrendSteady
+			}
+		}
+		result = append(result, *trend)
+	}
+	return result
+}
+
+func roundTonnage(value float64) float64 {
+	return math.Round(value*10) / 10
+}
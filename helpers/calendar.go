@@ -0,0 +1,76 @@
+package helpers
+
+import (
+	"time"
+	"wellnesspath/models"
+)
+
+// DateLayout is the YYYY-MM-DD format used for calendar dates in requests and responses.
+const DateLayout = "2006-01-02"
+
+const DefaultTimezone = "UTC"
+
+var weekdayNames = []string{"Monday", "Tuesday", "Wednesday", "Thursday", "Friday", "Saturday", "Sunday"}
+
+func IsValidTimezone(tz string) bool {
+	if tz == "" {
+		return false
+	}
+	_, err := time.LoadLocation(tz)
+	return err == nil
+}
+
+// LoadUserLocation returns the user's location, falling back to UTC for empty or unknown zones.
+func LoadUserLocation(tz string) *time.Location {
+	if tz == "" {
+		return time.UTC
+	}
+	loc, err := time.LoadLocation(tz)
+	if err != nil {
+		return time.UTC
+	}
+	return loc
+}
+
+// CivilDate keeps only the calendar date of t (as seen in t's own zone), as midnight UTC.
+// Dates normalized this way can be compared and subtracted without zone drift.
+func CivilDate(t time.Time) time.Time {
+	return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC)
+}
+
+// TodayIn returns the current calendar date in loc.
+func TodayIn(loc *time.Location) time.Time {
+	return CivilDate(time.Now().In(loc))
+}
+
+// PlanStartDate returns the civil start date of a plan; plans created before start dates existed use their creation date.
+func PlanStartDate(plan models.WorkoutPlan) time.Time {
+	if plan.StartDate.IsZero() {
+		return CivilDate(plan.CreatedAt)
+	}
+	return CivilDate(plan.StartDate)
+}
+
+func ParseCivilDate(value string) (time.Time, error) {
+	return time.Parse(DateLayout, value)
+}
+
+// DayNumberForDate maps a date onto plan day numbers, which follow ISO weekdays: 1 is Monday, 7 is Sunday.
+func DayNumberForDate(date time.Time) int {
+	weekday := int(date.Weekday())
+	if weekday == 0 {
+		return 7
+	}
+	return weekday
+}
+
+func WeekdayName(dayNumber int) string {
+	if dayNumber < 1 || dayNumber > 7 {
+		return ""
+	}
+	return weekdayNames[dayNumber-1]
+}
+
+func IsValidDayNumber(dayNumber int) bool {
+	return dayNumber >= 1 && dayNumber <= 7
+}
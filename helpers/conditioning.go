@@ -0,0 +1,213 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"math/rand"
+	"strings"
+	"wellnesspath/models"
+)
+
+// conditioningDose is the cardio a goal adds to each training day at one intensity. Duration
+// doses are a single steady bout; interval doses are Rounds of WorkSeconds on, RestSeconds off.
+type conditioningDose struct {
+	Mode            string
+	DurationSeconds int
+	Rounds          int
+	WorkSeconds     int
+	RestSeconds     int
+	HeartRateZone   int
+}
+
+var conditioningByGoal = map[string]map[string]conditioningDose{
+	"stamina": {
+		"beginner":     {Mode: models.ExerciseModeDuration, DurationSeconds: 15 * 60, HeartRateZone: 2},
+		"intermediate": {Mode: models.ExerciseModeDuration, DurationSeconds: 20 * 60, HeartRateZone: 3},
+		"advanced":     {Mode: models.ExerciseModeDuration, DurationSeconds: 25 * 60, HeartRateZone: 3},
+	},
+	"fat loss": {
+		"beginner":     {Mode: models.ExerciseModeInterval, Rounds: 6, WorkSeconds: 20, RestSeconds: 40, HeartRateZone: 4},
+		"intermediate": {Mode: models.ExerciseModeInterval, Rounds: 8, WorkSeconds: 30, RestSeconds: 30, HeartRateZone: 4},
+		"advanced":     {Mode: models.ExerciseModeInterval, Rounds: 10, WorkSeconds: 40, RestSeconds: 20, HeartRateZone: 4},
+	},
+}
+
+// runningPaceSeconds is the target pace per km for running and walking items, by intensity.
+var runningPaceSeconds = map[string]int{
+	"beginner":     450,
+	"intermediate": 360,
+	"advanced":     300,
+}
+
+// heartRateZoneRPE is the effort each heart-rate zone feels like, and zoneMET its energy cost.
+var (
+	heartRateZoneRPE = map[int]float64{1: 5, 2: 5.5, 3: 6.5, 4: 8, 5: 9}
+	zoneMET          = map[int]float64{1: 3.5, 2: 5.0, 3: 7.0, 4: 8.5, 5: 10.0}
+)
+
+const intervalMET = 8.0
+
+// coreBodyParts are trained by the catalogue's "Cardio" crunch variations, which are not conditioning.
+var coreBodyParts = map[string]bool{"abdominals": true}
+
+var (
+	// coreKeywords mark crunch variations filed under other body parts.
+	coreKeywords = []string{"crunch", "sit-through"}
+	// steadyStateKeywords mark modalities that can be kept up for a continuous bout. Everything
+	// else in the "Cardio" catalogue is plyometric (jumps) and is only prescribed as intervals.
+	steadyStateKeywords = []string{"run", "walk", "treadmill", "bike", "cycling", "rower", "rowing", "jumping rope", "skipping", "elliptical", "stair", "swim"}
+	distanceKeywords    = []string{"run", "walk", "treadmill"}
+)
+
+// IsRepsBased reports whether ex is prescribed as sets of reps. Only these count toward weekly
+// set volume, supersets and circuits, and progression.
+func IsRepsBased(ex models.WorkoutPlanExercise) bool {
+	return IsRepsMode(ex.Mode)
+}
+
+// IsRepsMode reports whether mode prescribes sets of reps; rows created before modes existed have none.
+func IsRepsMode(mode string) bool {
+	return mode == "" || mode == models.ExerciseModeReps
+}
+
+// IsConditioningExercise reports whether a catalogue exercise can be prescribed as cardio.
+func IsConditioningExercise(ex models.Exercise) bool {
+	return strings.EqualFold(ex.ExerciseType, "Cardio") && !coreBodyParts[strings.ToLower(ex.BodyPart)] &&
+		!nameHasKeyword(ex, coreKeywords)
+}
+
+// IsSteadyStateExercise reports whether a cardio exercise can be done as one continuous bout.
+func IsSteadyStateExercise(ex models.Exercise) bool {
+	return IsConditioningExercise(ex) && nameHasKeyword(ex, steadyStateKeywords)
+}
+
+// PickConditioningExercise picks the cardio exercise for a training day of goals that prescribe
+// one (Stamina and Fat Loss), preferring exercises the plan does not use yet. Goals with a steady
+// bout only pick steady-state modalities. It returns false for other goals or when the catalogue
+// has no suitable cardio exercise for the user.
+func PickConditioningExercise(rng *rand.Rand, exercises []models.Exercise, goal string, used map[uint64]bool) (models.Exercise, bool) {
+	doses, ok := conditioningByGoal[strings.ToLower(goal)]
+	if !ok {
+		return models.Exercise{}, false
+	}
+	steadyOnly := doses["intermediate"].Mode == models.ExerciseModeDuration
+
+	var fresh, all []models.Exercise
+	for _, ex := range exercises {
+		if !IsConditioningExercise(ex) || (steadyOnly && !IsSteadyStateExercise(ex)) {
+			continue
+		}
+		all = append(all, ex)
+		if !used[ex.ID] {
+			fresh = append(fresh, ex)
+		}
+	}
+	if len(fresh) > 0 {
+		return fresh[rng.Intn(len(fresh))], true
+	}
+	if len(all) > 0 {
+		return all[rng.Intn(len(all))], true
+	}
+	return models.Exercise{}, false
+}
+
+// NewConditioningItem prescribes ex as the goal's cardio for intensity. Running and walking items
+// also get a target pace and the distance it covers in the time. Plyometric exercises cannot be
+// prescribed as a steady bout.
+func NewConditioningItem(ex models.Exercise, goal, intensity string) (models.WorkoutPlanExercise, bool) {
+	doses, ok := conditioningByGoal[strings.ToLower(goal)]
+	if !ok {
+		return models.WorkoutPlanExercise{}, false
+	}
+	intensity = strings.ToLower(intensity)
+	dose, ok := doses[intensity]
+	if !ok {
+		dose = doses["intermediate"]
+	}
+	if dose.Mode == models.ExerciseModeDuration && !IsSteadyStateExercise(ex) {
+		return models.WorkoutPlanExercise{}, false
+	}
+
+	rpe := heartRateZoneRPE[dose.HeartRateZone]
+	item := models.WorkoutPlanExercise{
+		ExerciseID:    ex.ID,
+		Mode:          dose.Mode,
+		HeartRateZone: dose.HeartRateZone,
+		TargetRPE:     rpe,
+		TargetRIR:     RIRForRPE(rpe),
+	}
+
+	switch dose.Mode {
+	case models.ExerciseModeDuration:
+		item.Sets = 1
+		item.DurationSeconds = dose.DurationSeconds
+		if isDistanceExercise(ex) {
+			pace, ok := runningPaceSeconds[intensity]
+			if !ok {
+				pace = runningPaceSeconds["intermediate"]
+			}
+			item.TargetPace = FormatPace(pace)
+			item.DistanceMeters = int(math.Round(float64(dose.DurationSeconds)/float64(pace)*10)) * 100
+		}
+	case models.ExerciseModeInterval:
+		item.Sets = dose.Rounds
+		item.WorkSeconds = dose.WorkSeconds
+		item.RestSeconds = dose.RestSeconds
+	}
+	return item, true
+}
+
+func isDistanceExercise(ex models.Exercise) bool {
+	return nameHasKeyword(ex, distanceKeywords)
+}
+
+func nameHasKeyword(ex models.Exercise, keywords []string) bool {
+	name := strings.ToLower(ex.Name)
+	for _, keyword := range keywords {
+		if strings.Contains(name, keyword) {
+			return true
+		}
+	}
+	return false
+}
+
+// FormatPace renders a pace in seconds per km as "m:ss".
+func FormatPace(secondsPerKm int) string {
+	return fmt.Sprintf("%d:%02d", secondsPerKm/60, secondsPerKm%60)
+}
+
+// EstimatePlanExerciseSeconds is how long a plan exercise takes in any mode, including the
+// transition to the next one.
+func EstimatePlanExerciseSeconds(ex models.WorkoutPlanExercise) int {
+	switch ex.Mode {
+	case models.ExerciseModeDuration:
+		if ex.DurationSeconds <= 0 {
+			return 0
+		}
+		return ex.DurationSeconds + exerciseTransitionSeconds
+	case models.ExerciseModeInterval:
+		if ex.Sets <= 0 {
+			return 0
+		}
+		return ex.Sets*ex.WorkSeconds + (ex.Sets-1)*ex.RestSeconds + exerciseTransitionSeconds
+	default:
+		return EstimateExerciseSeconds(ex.Sets, ex.Reps, TrainingTargetsOf(ex))
+	}
+}
+
+// exerciseMET is the energy cost of a plan exercise: cardio by its heart-rate zone or interval
+// work, strength work by the exercise's goal tag.
+func exerciseMET(ex models.WorkoutPlanExercise, detail *models.Exercise) float64 {
+	switch ex.Mode {
+	case models.ExerciseModeInterval:
+		return intervalMET
+	case models.ExerciseModeDuration:
+		if met, ok := zoneMET[ex.HeartRateZone]; ok {
+			return met
+		}
+	}
+	if detail != nil {
+		return getMetValueForGoalTag(detail.GoalTag)
+	}
+	return getMetValueForGoalTag("")
+}
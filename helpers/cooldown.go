@@ -0,0 +1,144 @@
+package helpers
+
+import (
+	"strings"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+const (
+	maxCooldownStretches     = 5
+	maxRecoveryStretches     = 4
+	recoveryCardioSeconds    = 20 * 60
+	recoveryMobilitySeconds  = 45
+	recoveryStretchHoldScale = 2
+)
+
+// stretch is a static stretch, how long it takes (both sides where it is done one side at a
+// time) and the body parts it stretches.
+type stretch struct {
+	Name      string
+	Seconds   int
+	BodyParts []string
+}
+
+// recoveryDrills are the mobility drills of the active-recovery flow.
+var recoveryDrills = map[string]bool{"Cat-cow": true, "Hip circles": true, "Thoracic rotations": true}
+
+var stretches = []stretch{
+	{Name: "Standing quad stretch", Seconds: 60, BodyParts: []string{"quadriceps"}},
+	{Name: "Seated hamstring stretch", Seconds: 45, BodyParts: []string{"hamstrings"}},
+	{Name: "Figure-four glute stretch", Seconds: 60, BodyParts: []string{"glutes", "abductors"}},
+	{Name: "Wall calf stretch", Seconds: 60, BodyParts: []string{"calves"}},
+	{Name: "Butterfly stretch", Seconds: 45, BodyParts: []string{"adductors"}},
+	{Name: "Doorway chest stretch", Seconds: 45, BodyParts: []string{"chest"}},
+	{Name: "Cross-body shoulder stretch", Seconds: 60, BodyParts: []string{"shoulders"}},
+	{Name: "Overhead triceps stretch", Seconds: 60, BodyParts: []string{"triceps"}},
+	{Name: "Wall biceps and forearm stretch", Seconds: 60, BodyParts: []string{"biceps", "forearms"}},
+	{Name: "Kneeling lat stretch", Seconds: 45, BodyParts: []string{"lats"}},
+	{Name: "Child's pose", Seconds: 45, BodyParts: []string{"middle back", "lower back", "lats"}},
+	{Name: "Upper trap stretch", Seconds: 60, BodyParts: []string{"traps", "neck"}},
+	{Name: "Knees-to-chest stretch", Seconds: 45, BodyParts: []string{"lower back", "glutes"}},
+	{Name: "Cobra stretch", Seconds: 30, BodyParts: []string{"abdominals"}},
+}
+
+// BuildCooldown lays out the cool-down for a training day: static stretches for the body parts the
+// day trains, in the order the day lists them, one stretch per part at most.
+func BuildCooldown(bodyParts []string, exercises []models.WorkoutPlanExercise, limitations []dto.Limitation) []dto.BlockItem {
+	if len(exercises) == 0 {
+		return nil
+	}
+
+	var items []dto.BlockItem
+	for _, s := range stretchesFor(bodyParts, maxCooldownStretches, limitations) {
+		items = append(items, dto.BlockItem{
+			Block:           BlockCooldown,
+			Type:            BlockItemStretch,
+			Name:            s.Name,
+			DurationSeconds: s.Seconds,
+		})
+	}
+	return items
+}
+
+// BuildActiveRecovery lays out active recovery for a rest day: easy zone 1 cardio, a short mobility
+// flow and longer stretches for the body parts trained the day before. On plain rest days every item
+// is optional; on active-recovery days it is the day's prescription. Drills and stretches an
+// "avoid" limitation matches are left out.
+func BuildActiveRecovery(previousBodyParts []string, optional bool, limitations []dto.Limitation) []dto.BlockItem {
+	items := []dto.BlockItem{{
+		Block:           BlockRecovery,
+		Type:            BlockItemCardio,
+		Name:            "Easy walk, bike or swim (heart-rate zone 1)",
+		Optional:        optional,
+		DurationSeconds: recoveryCardioSeconds,
+	}}
+
+	for _, drill := range mobilityDrills {
+		if !recoveryDrills[drill.Name] || avoidsItem(drill.Name, drill.BodyParts, limitations) {
+			continue
+		}
+		items = append(items, dto.BlockItem{
+			Block:           BlockRecovery,
+			Type:            BlockItemMobility,
+			Name:            drill.Name,
+			Optional:        optional,
+			Reps:            10,
+			DurationSeconds: recoveryMobilitySeconds,
+		})
+	}
+
+	for _, s := range stretchesFor(previousBodyParts, maxRecoveryStretches, limitations) {
+		items = append(items, dto.BlockItem{
+			Block:           BlockRecovery,
+			Type:            BlockItemStretch,
+			Name:            s.Name,
+			Optional:        optional,
+			DurationSeconds: s.Seconds * recoveryStretchHoldScale,
+		})
+	}
+	return items
+}
+
+// PreviousTrainingBodyParts returns the body parts of the last training day before dayNumber,
+// wrapping around the week, or nil when the plan has no training day.
+func PreviousTrainingBodyParts(days []models.WorkoutPlanDay, dayNumber int) []string {
+	byNumber := map[int]models.WorkoutPlanDay{}
+	for _, day := range days {
+		byNumber[day.DayNumber] = day
+	}
+
+	for offset := 1; offset < 7; offset++ {
+		number := (dayNumber-offset+6)%7 + 1
+		if day, ok := byNumber[number]; ok && !models.IsRestDay(day) {
+			return DayBodyParts(day)
+		}
+	}
+	return nil
+}
+
+// stretchesFor picks up to limit stretches, walking bodyParts in order and taking the first
+// stretch for each part not covered yet that no "avoid" limitation matches.
+func stretchesFor(bodyParts []string, limit int, limitations []dto.Limitation) []stretch {
+	covered := map[string]bool{}
+	chosen := map[string]bool{}
+	var result []stretch
+	for _, part := range bodyParts {
+		part = strings.ToLower(part)
+		if covered[part] || len(result) >= limit {
+			continue
+		}
+		for _, s := range stretches {
+			if chosen[s.Name] || !Contains(s.BodyParts, part) || avoidsItem(s.Name, s.BodyParts, limitations) {
+				continue
+			}
+			chosen[s.Name] = true
+			for _, p := range s.BodyParts {
+				covered[p] = true
+			}
+			result = append(result, s)
+			break
+		}
+	}
+	return result
+}
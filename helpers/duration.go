@@ -0,0 +1,171 @@
+package helpers
+
+import (
+	"math"
+	"sort"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+const (
+	MinExercisesPerSession = 2
+	MaxExercisesPerSession = 8
+
+	// sessionWarmupSeconds is the warm-up allowance used before a day's exercises are known (see BuildWarmup).
+	sessionWarmupSeconds = 5 * 60
+	// groupTransitionSeconds is the time to move between exercises inside a superset or circuit round.
+	groupTransitionSeconds = 15
+)
+
+// EstimateSessionSeconds estimates how long a training day takes: the warm-up and cool-down blocks,
+// every exercise at its tempo with its rest, transitions between exercises, and grouped exercises
+// run as rounds (see BuildExerciseGroups for how rounds are counted). Pass exercises already
+// adjusted for phase.
+func EstimateSessionSeconds(blocks []dto.BlockItem, exercises []models.WorkoutPlanExercise, groups []models.WorkoutPlanGroup, phase string) int {
+	total := 0
+	grouped := map[int][]models.WorkoutPlanExercise{}
+	for _, ex := range exercises {
+		if ex.GroupNumber > 0 {
+			grouped[ex.GroupNumber] = append(grouped[ex.GroupNumber], ex)
+			continue
+		}
+		total += EstimatePlanExerciseSeconds(ex)
+	}
+
+	for _, group := range groups {
+		members := grouped[group.GroupNumber]
+		delete(grouped, group.GroupNumber)
+		if len(members) == 0 {
+			continue
+		}
+
+		rounds := AdjustPrescription(Prescription{Sets: group.Rounds}, phase).Sets
+		roundWork := 0
+		for _, ex := range members {
+			rounds = int(math.Max(float64(rounds), float64(ex.Sets)))
+			roundWork += ex.Reps * TempoSecondsPerRep(ex.Tempo)
+		}
+		roundWork += (len(members) - 1) * groupTransitionSeconds
+		total += rounds*roundWork + (rounds-1)*group.RestBetweenRoundsSeconds + exerciseTransitionSeconds
+	}
+
+	// members of a group that no longer exists are timed as straight sets
+	for _, members := range grouped {
+		for _, ex := range members {
+			total += EstimatePlanExerciseSeconds(ex)
+		}
+	}
+
+	if total == 0 {
+		return 0
+	}
+	return total + EstimateBlockSeconds(blocks)
+}
+
+// EstimateSessionMinutes is EstimateSessionSeconds rounded up to whole minutes.
+func EstimateSessionMinutes(blocks []dto.BlockItem, exercises []models.WorkoutPlanExercise, groups []models.WorkoutPlanGroup, phase string) int {
+	return int(math.Ceil(float64(EstimateSessionSeconds(blocks, exercises, groups, phase)) / 60))
+}
+
+// DayFitsDuration reports whether day, warm-up and cool-down included and grouped the way
+// GroupDayExercises will group it, fits in durationMinutes as prescribed. Generation uses it to
+// choose how many exercises a day gets before FitDayToDuration tunes their sets.
+func DayFitsDuration(day models.WorkoutPlanDay, exMap map[uint64]*models.Exercise, goal string, durationMinutes int, limitations []dto.Limitation) bool {
+	return estimateGroupedDay(day, exMap, goal, limitations) <= durationMinutes*60
+}
+
+// FitDayToDuration adjusts the sets of a generated training day so its estimated length (warm-up
+// and cool-down included, grouped the way GroupDayExercises will group it) is as close to
+// durationMinutes as possible without going over. While the day runs long, sets are trimmed
+// (down to MinSetsPerExercise) and, as a last resort, trailing exercises dropped (down to
+// MinExercisesPerSession); then sets are added (up to MaxSetsPerExercise) while another set still
+// fits. Cardio items are kept as prescribed.
+//
+// Run it after BalanceWeeklySets and before GroupDayExercises; the session length wins over the
+// weekly volume target when the two disagree. The remaining exercises are renumbered from 1.
+func FitDayToDuration(day *models.WorkoutPlanDay, exMap map[uint64]*models.Exercise, goal string, durationMinutes int, limitations []dto.Limitation) {
+	if durationMinutes <= 0 || models.IsRestDay(*day) || len(day.Exercises) == 0 {
+		return
+	}
+	budget := durationMinutes * 60
+
+	sort.SliceStable(day.Exercises, func(i, j int) bool { return day.Exercises[i].Order < day.Exercises[j].Order })
+
+	for estimateGroupedDay(*day, exMap, goal, limitations) > budget {
+		if i := exerciseWithMostSets(day.Exercises); i >= 0 {
+			day.Exercises[i].Sets--
+			continue
+		}
+		i := lastRepsBasedExercise(day.Exercises)
+		if i < 0 || countRepsBased(day.Exercises) <= MinExercisesPerSession {
+			break
+		}
+		day.Exercises = append(day.Exercises[:i], day.Exercises[i+1:]...)
+	}
+	for i := range day.Exercises {
+		day.Exercises[i].Order = i + 1
+	}
+
+	for {
+		i := exerciseWithFewestSets(day.Exercises)
+		if i < 0 {
+			return
+		}
+		day.Exercises[i].Sets++
+		if estimateGroupedDay(*day, exMap, goal, limitations) > budget {
+			day.Exercises[i].Sets--
+			return
+		}
+	}
+}
+
+// estimateGroupedDay estimates day, warm-up and cool-down included, as it will be once grouped,
+// without changing it.
+func estimateGroupedDay(day models.WorkoutPlanDay, exMap map[uint64]*models.Exercise, goal string, limitations []dto.Limitation) int {
+	day.Exercises = append([]models.WorkoutPlanExercise(nil), day.Exercises...)
+	GroupDayExercises(&day, exMap, goal)
+	parts := DayBodyParts(day)
+	blocks := append(BuildWarmup(parts, day.Exercises, exMap, limitations), BuildCooldown(parts, day.Exercises, limitations)...)
+	return EstimateSessionSeconds(blocks, day.Exercises, day.Groups, "")
+}
+
+func lastRepsBasedExercise(exercises []models.WorkoutPlanExercise) int {
+	for i := len(exercises) - 1; i >= 0; i-- {
+		if IsRepsBased(exercises[i]) {
+			return i
+		}
+	}
+	return -1
+}
+
+func countRepsBased(exercises []models.WorkoutPlanExercise) int {
+	count := 0
+	for _, ex := range exercises {
+		if IsRepsBased(ex) {
+			count++
+		}
+	}
+	return count
+}
+
+// exerciseWithMostSets returns the last exercise with the most sets that can still lose one, or -1.
+func exerciseWithMostSets(exercises []models.WorkoutPlanExercise) int {
+	best := -1
+	for i, ex := range exercises {
+		if IsRepsBased(ex) && ex.Sets > MinSetsPerExercise && (best < 0 || ex.Sets >= exercises[best].Sets) {
+			best = i
+		}
+	}
+	return best
+}
+
+// exerciseWithFewestSets returns the first exercise with the fewest sets that can still gain one, or -1.
+func exerciseWithFewestSets(exercises []models.WorkoutPlanExercise) int {
+	best := -1
+	for i, ex := range exercises {
+		if IsRepsBased(ex) && ex.Sets < MaxSetsPerExercise && (best < 0 || ex.Sets < exercises[best].Sets) {
+			best = i
+		}
+	}
+	return best
+}
@@ -0,0 +1,54 @@
+package helpers
+
+import (
+	"sort"
+	"strings"
+	"sync"
+)
+
+// focusRegistry maps focus names to body parts. It is loaded from the focus_mappings table at
+// startup (see services.LoadFocusRegistry) and reloaded after admin edits.
+var focusRegistry = struct {
+	sync.RWMutex
+	parts map[string][]string
+}{parts: map[string][]string{}}
+
+// SetFocusMappings replaces the whole registry.
+func SetFocusMappings(mappings map[string][]string) {
+	parts := make(map[string][]string, len(mappings))
+	for focus, bodyParts := range mappings {
+		parts[strings.ToLower(focus)] = append([]string(nil), bodyParts...)
+	}
+
+	focusRegistry.Lock()
+	focusRegistry.parts = parts
+	focusRegistry.Unlock()
+}
+
+// GetBodyPartsForFocus returns the body parts mapped to focus, or an empty list for unmapped focuses.
+func GetBodyPartsForFocus(focus string) []string {
+	focusRegistry.RLock()
+	defer focusRegistry.RUnlock()
+
+	parts, ok := focusRegistry.parts[strings.ToLower(focus)]
+	if !ok {
+		return []string{}
+	}
+	return append([]string(nil), parts...)
+}
+
+// BuiltInSplitFocusNames lists the focuses the built-in splits depend on.
+func BuiltInSplitFocusNames() []string {
+	seen := map[string]bool{}
+	var names []string
+	for _, split := range builtInSplits {
+		for _, focus := range split.focuses {
+			if !seen[focus] {
+				seen[focus] = true
+				names = append(names, focus)
+			}
+		}
+	}
+	sort.Strings(names)
+	return names
+}
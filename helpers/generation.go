@@ -2,36 +2,17 @@ package helpers
 
 import (
 	"fmt"
+	"math"
 	"strings"
 	"wellnesspath/dto"
 	"wellnesspath/models"
 )
 
-func GetSplitFocuses(splitType string, frequency int) []string {
-	switch strings.ToLower(splitType) {
-	case "push/pull/legs":
-		return repeatSequence([]string{"Push", "Pull", "Legs"}, frequency)
-	case "upper/lower":
-		return repeatSequence([]string{"Upper", "Lower"}, frequency)
-	case "full body":
-		return repeatSequence([]string{"Full Body"}, frequency)
-	case "bro split":
-		return repeatSequence([]string{"Chest", "Back", "Legs", "Shoulders", "Arms"}, frequency)
-	default:
-		return repeatSequence([]string{"General"}, frequency)
-	}
-}
-
-func repeatSequence(pattern []string, total int) []string {
-	var result []string
-	for i := 0; i < total; i++ {
-		result = append(result, pattern[i%len(pattern)])
-	}
-	return result
+func FilterExercisesByFocus(exercises []models.Exercise, focus string) []models.Exercise {
+	return FilterExercisesByBodyParts(exercises, GetBodyPartsForFocus(focus))
 }
 
-func FilterExercisesByFocus(exercises []models.Exercise, focus string) []models.Exercise {
-	validParts := GetBodyPartsForFocus(focus)
+func FilterExercisesByBodyParts(exercises []models.Exercise, validParts []string) []models.Exercise {
 	var result []models.Exercise
 
 	for _, e := range exercises {
@@ -106,11 +87,25 @@ func GenerateTrainingAdvice(profile *models.Profile) string {
 	}
 }
 
-func CalculateCalories(profile *models.Profile) dto.CaloriesBurned {
-	base := float64(profile.DurationPerSession) * 5.0 // average 5 cal/min
-	perSession := base
-	weekly := perSession * float64(profile.Frequency)
-	total := weekly * 4
+// CalculateCalories estimates the calories of the plan's training days (see CalculateTodayCalories)
+// and averages them per session. Without a usable estimate it falls back to 5 cal/min of DurationPerSession.
+func CalculateCalories(profile *models.Profile, days []models.WorkoutPlanDay, exMap map[uint64]*models.Exercise, weeks int) dto.CaloriesBurned {
+	weekly, sessions := 0.0, 0
+	for _, day := range days {
+		if calories := CalculateTodayCalories(day.Exercises, exMap, profile.TargetWeight); calories > 0 {
+			weekly += calories
+			sessions++
+		}
+	}
+
+	perSession := 0.0
+	if sessions > 0 {
+		perSession = weekly / float64(sessions)
+	} else {
+		perSession = float64(profile.DurationPerSession) * 5.0 // average 5 cal/min
+		weekly = perSession * float64(profile.Frequency)
+	}
+	total := weekly * float64(weeks)
 
 	return dto.CaloriesBurned{
 		PerSession: perSession,
@@ -135,23 +130,17 @@ func getMetValueForGoalTag(goalTag string) float64 {
 	}
 }
 
-func CalculateTodayCalories(allGoalTags []string, userWeight float64) float64 {
+// CalculateTodayCalories estimates the calories of a day's exercises as MET × weight × hours,
+// timing each exercise with EstimatePlanExerciseSeconds so cardio counts for its actual duration.
+func CalculateTodayCalories(exercises []models.WorkoutPlanExercise, exMap map[uint64]*models.Exercise, userWeight float64) float64 {
 	var totalCalories float64
 
-	// Iterate through all GoalTags
-	for _, goalTag := range allGoalTags {
-		// Get the MET value for the goalTag
-		metValue := getMetValueForGoalTag(goalTag)
-
-		// Calculate calories burned for this GoalTag using a constant time-based factor (e.g., 1 hour)
-		// Assuming the exercise lasts for 1 hour
-		caloriesBurned := metValue * userWeight // assuming it's per hour
-
-		// Add to total calories
-		totalCalories += caloriesBurned
+	for _, ex := range exercises {
+		hours := float64(EstimatePlanExerciseSeconds(ex)) / 3600
+		totalCalories += exerciseMET(ex, exMap[ex.ExerciseID]) * userWeight * hours
 	}
 
-	return totalCalories
+	return math.Round(totalCalories*10) / 10
 }
 
 func GenerateNutrition(profile *models.Profile) dto.DailyNutritionRecommendation {
@@ -177,153 +166,6 @@ func GenerateNutrition(profile *models.Profile) dto.DailyNutritionRecommendation
 	}
 }
 
-func GetBodyPartsForFocus(focus string) []string {
-	switch strings.ToLower(focus) {
-	case "push":
-		return []string{"Chest", "Shoulders", "Triceps"}
-	case "pull":
-		return []string{"Back", "Lats", "Biceps", "Forearms"}
-	case "legs":
-		return []string{"Quadriceps", "Glutes", "Hamstrings", "Calves", "Lower Back"}
-	case "upper":
-		return []string{"Chest", "Back", "Shoulders", "Biceps", "Triceps", "Forearms"}
-	case "lower":
-		return []string{"Quadriceps", "Hamstrings", "Glutes", "Calves", "Lower Back"}
-	case "full body":
-		return []string{
-			"Chest", "Back", "Quadriceps", "Hamstrings", "Calves",
-			"Shoulders", "Biceps", "Triceps", "Forearms", "Glutes", "Abdominals",
-		}
-	case "chest":
-		return []string{"Chest"}
-	case "back":
-		return []string{"Back", "Lats"}
-	case "shoulders":
-		return []string{"Shoulders"}
-	case "arms":
-		return []string{"Biceps", "Triceps", "Forearms"}
-	default:
-		return []string{}
-	}
-}
-
-func SelectTailoredExercises(exercises []models.Exercise, profile *models.Profile, focus string, maxCount int) []models.Exercise {
-	validParts := GetBodyPartsForFocus(focus)
-	intensityRank := map[string]int{
-		"Beginner":     1,
-		"Intermediate": 2,
-		"Advanced":     3,
-	}
-	userRank := intensityRank[strings.ToLower(profile.Intensity)]
-
-	// Tier 1: Strict (match all: bodyPart, goal, difficulty, no dup category)
-	selected := filterWithCriteria(exercises, validParts, profile.Goal, userRank, true, true, maxCount)
-	if len(selected) >= maxCount {
-		return selected
-	}
-
-	// Tier 2: Relax goal_tag match (allow all goals)
-	selected = filterWithCriteria(exercises, validParts, profile.Goal, userRank, false, true, maxCount)
-	if len(selected) >= maxCount {
-		return selected
-	}
-
-	// Tier 3: Relax difficulty (ignore difficulty level)
-	selected = filterWithCriteria(exercises, validParts, profile.Goal, -1, false, false, maxCount)
-	if len(selected) >= maxCount {
-		return selected
-	}
-
-	// Tier 4: Relax category uniqueness
-	selected = filterWithCriteria(exercises, validParts, profile.Goal, -1, false, false, maxCount)
-	return selected
-}
-
-func filterWithCriteria(exs []models.Exercise, validParts []string, goal string, maxRank int, strictGoal bool, uniqueCategory bool, maxCount int) []models.Exercise {
-	selected := []models.Exercise{}
-	usedCategories := map[string]bool{}
-
-	for _, ex := range exs {
-		if !Contains(validParts, ex.BodyPart) {
-			continue
-		}
-
-		if maxRank != -1 {
-			intensityRank := map[string]int{
-				"Beginner":     1,
-				"Intermediate": 2,
-				"Advanced":     3,
-			}
-			exRank, ok := intensityRank[strings.ToLower(ex.Difficulty)]
-			if !ok {
-				exRank = 3
-			}
-			if exRank > maxRank {
-				continue
-			}
-		}
-
-		if strictGoal && !strings.EqualFold(ex.GoalTag, goal) && !strings.EqualFold(ex.GoalTag, "General Fitness") {
-			continue
-		}
-
-		if uniqueCategory && usedCategories[ex.Category] {
-			continue
-		}
-
-		selected = append(selected, ex)
-		usedCategories[ex.Category] = true
-
-		if len(selected) == maxCount {
-			break
-		}
-	}
-
-	return selected
-}
-
-func FilterWithBodyPartCoverage(exercises []models.Exercise, validParts []string, goal string, intensity string, count int) []models.Exercise {
-	grouped := make(map[string][]models.Exercise)
-
-	for _, ex := range exercises {
-		if Contains(validParts, ex.BodyPart) {
-			grouped[ex.BodyPart] = append(grouped[ex.BodyPart], ex)
-		}
-	}
-
-	selected := []models.Exercise{}
-	used := map[uint64]bool{}
-
-	// Ambil 1 dari setiap grup utama (Chest, Back, etc)
-	for _, list := range grouped {
-		for _, ex := range list {
-			if !used[ex.ID] {
-				selected = append(selected, ex)
-				used[ex.ID] = true
-				if len(selected) >= count {
-					return selected
-				}
-				break
-			}
-		}
-	}
-
-	// Tambahkan sisanya jika belum cukup
-	for _, list := range grouped {
-		for _, ex := range list {
-			if !used[ex.ID] {
-				selected = append(selected, ex)
-				used[ex.ID] = true
-				if len(selected) >= count {
-					return selected
-				}
-			}
-		}
-	}
-
-	return selected
-}
-
 // Helper contains() function
 func Contains(slice []string, val string) bool {
 	for _, item := range slice {
@@ -334,10 +176,21 @@ func Contains(slice []string, val string) bool {
 	return false
 }
 
-func ValidateSplitAndRestDays(splitType string, frequency int, restDays []int) error {
+// ValidateSplitAndRestDays checks the rest days and that split can be trained on the days left.
+func ValidateSplitAndRestDays(split SplitDefinition, frequency int, restDays []int) error {
 	if len(restDays) > 7 {
 		return fmt.Errorf("invalid number of rest days (max 7)")
 	}
+	seen := map[int]bool{}
+	for _, d := range restDays {
+		if !IsValidDayNumber(d) {
+			return fmt.Errorf("invalid rest day %d: days run from 1 (Monday) to 7 (Sunday)", d)
+		}
+		if seen[d] {
+			return fmt.Errorf("rest day %d is listed more than once", d)
+		}
+		seen[d] = true
+	}
 
 	availableDays := 7 - len(restDays)
 	if availableDays < frequency {
@@ -345,35 +198,10 @@ func ValidateSplitAndRestDays(splitType string, frequency int, restDays []int) e
 			len(restDays), availableDays, frequency)
 	}
 
-	switch normalizeSplit(splitType) {
-	case "push/pull/legs":
-		if frequency < 3 {
-			return fmt.Errorf("push/pull/legs split requires at least 3 workout days")
-		}
-		if frequency%3 != 0 {
-			return fmt.Errorf("push/pull/legs split is based on a 3-day cycle; valid frequencies are 3 or 6")
-		}
-	case "upper/lower":
-		if frequency < 2 {
-			return fmt.Errorf("upper/lower split requires at least 2 workout days")
-		}
-		if frequency%2 != 0 {
-			return fmt.Errorf("upper/lower split is based on a 2-day cycle; valid frequencies are 2, 4, or 6")
-		}
-	case "bro split":
-		if frequency != 5 {
-			return fmt.Errorf("bro split requires exactly 5 workout days")
-		}
-	case "full body":
-		if frequency < 2 {
-			return fmt.Errorf("full body split requires at least 2 sessions per week")
-		}
-		if frequency > 4 {
-			return fmt.Errorf("full body split should not exceed 4 sessions per week")
-		}
+	if err := split.ValidateFocuses(); err != nil {
+		return err
 	}
-
-	return nil
+	return split.ValidateFrequency(frequency)
 }
 
 func GetWorkoutDays(restDays []int, frequency int) ([]int, error) {
@@ -398,37 +226,27 @@ func ContainsInt(slice []int, val int) bool {
 	return false
 }
 
-func normalizeSplit(split string) string {
-	switch strings.ToLower(split) {
-	case "push/pull/legs", "upper/lower", "full body", "bro split":
-		return strings.ToLower(split)
-	default:
-		return "general"
-	}
-}
-
-func PrepareWorkoutDays(splitType string, frequency int, restDays []int) ([]int, error) {
-	if err := ValidateSplitAndRestDays(splitType, frequency, restDays); err != nil {
+func PrepareWorkoutDays(split SplitDefinition, frequency int, restDays []int) ([]int, error) {
+	if err := ValidateSplitAndRestDays(split, frequency, restDays); err != nil {
 		return nil, err
 	}
 	return GetWorkoutDays(restDays, frequency)
 }
 
-func CalculateMaxExercises(durationMinutes int, repsPerExercise int) int {
-	secondsPerRep := 4
-	setsPerExercise := 3
-	transitionTime := 30
-
-	exerciseTime := (repsPerExercise * secondsPerRep * setsPerExercise) + transitionTime
+// CalculateMaxExercises fits exercises of repsPerExercise reps into a session after the warm-up,
+// timing each one with the rest and tempo in targets (see EstimateExerciseSeconds). It is a first
+// guess; generation corrects it with DayFitsDuration and FitDayToDuration then tunes the sets.
+func CalculateMaxExercises(durationMinutes int, repsPerExercise int, targets TrainingTargets) int {
+	exerciseTime := EstimateExerciseSeconds(DefaultSetsPerExercise, repsPerExercise, targets)
 
-	totalAvailable := durationMinutes * 60
+	totalAvailable := durationMinutes*60 - sessionWarmupSeconds
 
 	maxExercises := totalAvailable / exerciseTime
 
-	if maxExercises < 2 {
-		return 2
-	} else if maxExercises > 6 {
-		return 6
+	if maxExercises < MinExercisesPerSession {
+		return MinExercisesPerSession
+	} else if maxExercises > MaxExercisesPerSession {
+		return MaxExercisesPerSession
 	}
 	return maxExercises
 }
@@ -0,0 +1,169 @@
+package helpers
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+const (
+	supersetRestSeconds = 60
+	circuitRestSeconds  = 90
+	giantSetRestSeconds = 120
+)
+
+// groupSizeForGoal is the largest group each goal trains back to back; goals not listed use straight sets.
+var groupSizeForGoal = map[string]int{
+	"fat loss": 2,
+	"stamina":  4,
+}
+
+// GroupDayExercises groups a training day's exercises for goals that train them back to back:
+// Fat Loss pairs them into supersets and Stamina runs circuits of up to four. Each group mixes
+// body parts where it can, so one muscle rests while the next works. Members are reordered to
+// sit next to each other and the day's Groups are replaced; cardio items stay ungrouped at the
+// end of the day. Run it after the sets are final.
+func GroupDayExercises(day *models.WorkoutPlanDay, exMap map[uint64]*models.Exercise, goal string) {
+	day.Groups = nil
+	maxSize, ok := groupSizeForGoal[strings.ToLower(goal)]
+	if !ok || models.IsRestDay(*day) {
+		return
+	}
+
+	sort.SliceStable(day.Exercises, func(i, j int) bool { return day.Exercises[i].Order < day.Exercises[j].Order })
+	var exercises, cardio []models.WorkoutPlanExercise
+	for _, ex := range day.Exercises {
+		if IsRepsBased(ex) {
+			exercises = append(exercises, ex)
+		} else {
+			cardio = append(cardio, ex)
+		}
+	}
+	if len(exercises) < 2 {
+		return
+	}
+
+	groupCount := int(math.Ceil(float64(len(exercises)) / float64(maxSize)))
+	baseSize, larger := len(exercises)/groupCount, len(exercises)%groupCount
+
+	used := make([]bool, len(exercises))
+	var ordered []models.WorkoutPlanExercise
+	for g := 0; g < groupCount; g++ {
+		size := baseSize
+		if g < larger {
+			size++
+		}
+
+		members := pickGroupMembers(exercises, used, exMap, size)
+		groupNumber := 0
+		if len(members) > 1 {
+			groupNumber = len(day.Groups) + 1
+			day.Groups = append(day.Groups, newPlanGroup(groupNumber, members, exercises, exMap))
+		}
+		for _, idx := range members {
+			ex := exercises[idx]
+			ex.GroupNumber = groupNumber
+			ex.Order = len(ordered) + 1
+			ordered = append(ordered, ex)
+		}
+	}
+	for _, ex := range cardio {
+		ex.GroupNumber = 0
+		ex.Order = len(ordered) + 1
+		ordered = append(ordered, ex)
+	}
+	day.Exercises = ordered
+}
+
+// pickGroupMembers takes the first unused exercise and fills the group with the next unused ones,
+// preferring body parts the group does not train yet.
+func pickGroupMembers(exercises []models.WorkoutPlanExercise, used []bool, exMap map[uint64]*models.Exercise, size int) []int {
+	var members []int
+	parts := map[string]bool{}
+	take := func(i int) {
+		used[i] = true
+		members = append(members, i)
+		parts[exerciseBodyPart(exercises[i], exMap)] = true
+	}
+
+	for _, mixed := range []bool{true, false} {
+		for i := range exercises {
+			if len(members) >= size {
+				return members
+			}
+			if used[i] {
+				continue
+			}
+			if len(members) == 0 || !mixed || !parts[exerciseBodyPart(exercises[i], exMap)] {
+				take(i)
+			}
+		}
+	}
+	return members
+}
+
+func newPlanGroup(groupNumber int, members []int, exercises []models.WorkoutPlanExercise, exMap map[uint64]*models.Exercise) models.WorkoutPlanGroup {
+	rounds := 0
+	parts := map[string]bool{}
+	for _, idx := range members {
+		rounds = int(math.Max(float64(rounds), float64(exercises[idx].Sets)))
+		parts[exerciseBodyPart(exercises[idx], exMap)] = true
+	}
+
+	group := models.WorkoutPlanGroup{GroupNumber: groupNumber, Rounds: rounds}
+	switch {
+	case len(members) == 2:
+		group.Type, group.RestBetweenRoundsSeconds = models.GroupTypeSuperset, supersetRestSeconds
+	case len(parts) == 1:
+		group.Type, group.RestBetweenRoundsSeconds = models.GroupTypeGiantSet, giantSetRestSeconds
+	default:
+		group.Type, group.RestBetweenRoundsSeconds = models.GroupTypeCircuit, circuitRestSeconds
+	}
+	return group
+}
+
+func exerciseBodyPart(ex models.WorkoutPlanExercise, exMap map[uint64]*models.Exercise) string {
+	if detail, ok := exMap[ex.ExerciseID]; ok && detail != nil {
+		return strings.ToLower(detail.BodyPart)
+	}
+	return ""
+}
+
+// BuildExerciseGroups describes a day's groups for responses. exercises must already be adjusted
+// for phase; a group runs as many rounds as its stored (phase-adjusted) rounds or its members' sets,
+// whichever is higher, so sets added by progression show up as extra rounds.
+func BuildExerciseGroups(groups []models.WorkoutPlanGroup, exercises []models.WorkoutPlanExercise, phase string) []dto.ExerciseGroup {
+	if len(groups) == 0 {
+		return nil
+	}
+
+	sorted := append([]models.WorkoutPlanGroup(nil), groups...)
+	sort.Slice(sorted, func(i, j int) bool { return sorted[i].GroupNumber < sorted[j].GroupNumber })
+
+	var result []dto.ExerciseGroup
+	for _, group := range sorted {
+		rounds := AdjustPrescription(Prescription{Sets: group.Rounds}, phase).Sets
+		var members []uint64
+		for _, ex := range exercises {
+			if ex.GroupNumber != group.GroupNumber {
+				continue
+			}
+			members = append(members, ex.ID)
+			rounds = int(math.Max(float64(rounds), float64(ex.Sets)))
+		}
+		if len(members) == 0 {
+			continue
+		}
+
+		result = append(result, dto.ExerciseGroup{
+			GroupNumber:              group.GroupNumber,
+			Type:                     group.Type,
+			Rounds:                   rounds,
+			RestBetweenRoundsSeconds: group.RestBetweenRoundsSeconds,
+			PlanExerciseIDs:          members,
+		})
+	}
+	return result
+}
@@ -0,0 +1,144 @@
+package helpers
+
+import (
+	"crypto/rand"
+	"encoding/hex"
+	"fmt"
+	"sort"
+	"strings"
+	"time"
+	"unicode/utf8"
+	"wellnesspath/models"
+)
+
+const icalMaxLineOctets = 75
+
+// GenerateFeedToken returns a random, URL-safe secret for calendar subscriptions.
+func GenerateFeedToken() (string, error) {
+	buf := make([]byte, 24)
+	if _, err := rand.Read(buf); err != nil {
+		return "", fmt.Errorf("failed to generate feed token: %w", err)
+	}
+	return hex.EncodeToString(buf), nil
+}
+
+// BuildICalendar renders the plan as an RFC 5545 feed: one all-day event per training day,
+// recurring weekly from the first matching date on or after the plan start date.
+func BuildICalendar(plan models.WorkoutPlan, exMap map[uint64]*models.Exercise, timezone string, stamp time.Time) string {
+	var b strings.Builder
+
+	writeICalLine(&b, "BEGIN:VCALENDAR")
+	writeICalLine(&b, "VERSION:2.0")
+	writeICalLine(&b, "PRODID:-//WellnessPath//Workout Plan//EN")
+	writeICalLine(&b, "CALSCALE:GREGORIAN")
+	writeICalLine(&b, "METHOD:PUBLISH")
+	writeICalLine(&b, "X-WR-CALNAME:"+escapeICalText("WellnessPath Workout Plan"))
+	if timezone != "" {
+		writeICalLine(&b, "X-WR-TIMEZONE:"+timezone)
+	}
+
+	days := append([]models.WorkoutPlanDay(nil), plan.Days...)
+	sort.Slice(days, func(i, j int) bool { return days[i].DayNumber < days[j].DayNumber })
+
+	start := PlanStartDate(plan)
+	for _, day := range days {
+		if models.IsRestDay(day) {
+			continue
+		}
+
+		first := start
+		for DayNumberForDate(first) != day.DayNumber {
+			first = first.AddDate(0, 0, 1)
+		}
+
+		writeICalLine(&b, "BEGIN:VEVENT")
+		writeICalLine(&b, fmt.Sprintf("UID:plan-%d-day-%d@wellnesspath", plan.ID, day.ID))
+		writeICalLine(&b, "DTSTAMP:"+stamp.UTC().Format("20060102T150405Z"))
+		writeICalLine(&b, "DTSTART;VALUE=DATE:"+first.Format("20060102"))
+		writeICalLine(&b, "DTEND;VALUE=DATE:"+first.AddDate(0, 0, 1).Format("20060102"))
+		writeICalLine(&b, "RRULE:FREQ=WEEKLY")
+		writeICalLine(&b, "SUMMARY:"+escapeICalText(day.Focus+" Workout"))
+		writeICalLine(&b, "DESCRIPTION:"+escapeICalText(describeDayExercises(day, exMap)))
+		writeICalLine(&b, "TRANSP:TRANSPARENT")
+		writeICalLine(&b, "END:VEVENT")
+	}
+
+	writeICalLine(&b, "END:VCALENDAR")
+	return b.String()
+}
+
+func describeDayExercises(day models.WorkoutPlanDay, exMap map[uint64]*models.Exercise) string {
+	exercises := append([]models.WorkoutPlanExercise(nil), day.Exercises...)
+	sort.Slice(exercises, func(i, j int) bool { return exercises[i].Order < exercises[j].Order })
+
+	var lines []string
+	for _, ex := range exercises {
+		detail, ok := exMap[ex.ExerciseID]
+		if !ok {
+			continue
+		}
+		lines = append(lines, fmt.Sprintf("%d. %s - %s", len(lines)+1, detail.Name, describePrescription(ex)))
+	}
+	return strings.Join(lines, "\n")
+}
+
+// describePrescription renders sets x reps (and load) for strength work, minutes and heart-rate
+// zone for a steady bout, and rounds of work and rest for intervals.
+func describePrescription(ex models.WorkoutPlanExercise) string {
+	switch ex.Mode {
+	case models.ExerciseModeDuration:
+		text := fmt.Sprintf("%g min", float64(ex.DurationSeconds)/60)
+		if ex.HeartRateZone > 0 {
+			text += fmt.Sprintf(", zone %d", ex.HeartRateZone)
+		}
+		if ex.DistanceMeters > 0 {
+			text += fmt.Sprintf(" (%g km", float64(ex.DistanceMeters)/1000)
+			if ex.TargetPace != "" {
+				text += fmt.Sprintf(" @ %s/km", ex.TargetPace)
+			}
+			text += ")"
+		}
+		return text
+	case models.ExerciseModeInterval:
+		text := fmt.Sprintf("%d x %ds on / %ds off", ex.Sets, ex.WorkSeconds, ex.RestSeconds)
+		if ex.HeartRateZone > 0 {
+			text += fmt.Sprintf(", zone %d", ex.HeartRateZone)
+		}
+		return text
+	default:
+		text := fmt.Sprintf("%d x %d", ex.Sets, ex.Reps)
+		if ex.Load > 0 {
+			text += fmt.Sprintf(" @ %g kg", ex.Load)
+		}
+		return text
+	}
+}
+
+func escapeICalText(value string) string {
+	replacer := strings.NewReplacer(
+		`\`, `\\`,
+		";", `\;`,
+		",", `\,`,
+		"\r\n", `\n`,
+		"\n", `\n`,
+	)
+	return replacer.Replace(value)
+}
+
+// writeICalLine folds content lines longer than 75 octets without splitting UTF-8 sequences.
+func writeICalLine(b *strings.Builder, line string) {
+	limit := icalMaxLineOctets
+	for len(line) > limit {
+		cut := limit
+		for cut > 0 && !utf8.RuneStart(line[cut]) {
+			cut--
+		}
+		b.WriteString(line[:cut])
+		b.WriteString("\r\n ")
+		line = line[cut:]
+		// continuation lines lose one octet to the leading space
+		limit = icalMaxLineOctets - 1
+	}
+	b.WriteString(line)
+	b.WriteString("\r\n")
+}
@@ -0,0 +1,126 @@
+package helpers
+
+import (
+	"encoding/json"
+	"sort"
+	"strings"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+const (
+	LimitationTypeBodyPart = "body_part"
+	LimitationTypeMovement = "movement"
+
+	// LimitationSeverityAvoid removes matching exercises entirely.
+	LimitationSeverityAvoid = "avoid"
+	// LimitationSeverityLimit keeps matching exercises, but ranks them lower and allows one per day.
+	LimitationSeverityLimit = "limit"
+)
+
+// movementPatterns maps each movement pattern to keywords found in the names of exercises that use it.
+// The catalogue has no movement column, so names are the only signal.
+var movementPatterns = map[string][]string{
+	"overhead_press": {"overhead", "military press", "shoulder press", "push press", "arnold", "jerk", "snatch", "handstand"},
+	"hinge":          {"deadlift", "good morning", "hyperextension", "kettlebell swing", "rack pull"},
+	"squat":          {"squat", "leg press"},
+	"lunge":          {"lunge", "split squat", "step-up"},
+	"jump":           {"jump", "burpee"},
+	"dip":            {"dip"},
+	"pull_up":        {"pull-up", "pullup", "chin-up", "chins", "muscle up"},
+	"olympic_lift":   {"clean", "snatch", "jerk"},
+}
+
+// MovementPatterns lists the movement patterns a limitation can refer to.
+func MovementPatterns() []string {
+	names := make([]string, 0, len(movementPatterns))
+	for name := range movementPatterns {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+	return names
+}
+
+func IsValidMovementPattern(pattern string) bool {
+	_, ok := movementPatterns[strings.ToLower(pattern)]
+	return ok
+}
+
+// MovementKeywords returns the lower-case exercise name keywords of a movement pattern.
+func MovementKeywords(pattern string) []string {
+	return movementPatterns[strings.ToLower(pattern)]
+}
+
+func IsValidLimitationSeverity(severity string) bool {
+	return severity == LimitationSeverityAvoid || severity == LimitationSeverityLimit
+}
+
+func EncodeLimitations(limitations []dto.Limitation) (string, error) {
+	if len(limitations) == 0 {
+		return "", nil
+	}
+	data, err := json.Marshal(limitations)
+	if err != nil {
+		return "", err
+	}
+	return string(data), nil
+}
+
+func DecodeLimitations(jsonStr string) []dto.Limitation {
+	var result []dto.Limitation
+	if jsonStr == "" {
+		return []dto.Limitation{}
+	}
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return []dto.Limitation{}
+	}
+	return result
+}
+
+// MatchesLimitation reports whether ex trains the limited body part or uses the limited movement.
+func MatchesLimitation(ex models.Exercise, limitation dto.Limitation) bool {
+	switch limitation.Type {
+	case LimitationTypeBodyPart:
+		return strings.EqualFold(ex.BodyPart, limitation.Target)
+	case LimitationTypeMovement:
+		name := strings.ToLower(ex.Name)
+		for _, keyword := range MovementKeywords(limitation.Target) {
+			if strings.Contains(name, keyword) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// avoidsItem reports whether a warm-up, cool-down or recovery item named name that works
+// bodyParts falls under one of the "avoid" limitations.
+func avoidsItem(name string, bodyParts []string, limitations []dto.Limitation) bool {
+	for _, limitation := range limitations {
+		if limitation.Severity != LimitationSeverityAvoid {
+			continue
+		}
+		for _, part := range bodyParts {
+			if MatchesLimitation(models.Exercise{Name: name, BodyPart: part}, limitation) {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// LimitationSeverity returns the strictest severity among the limitations ex matches,
+// or an empty string when none applies.
+func LimitationSeverity(ex models.Exercise, limitations []dto.Limitation) string {
+	severity := ""
+	for _, limitation := range limitations {
+		if !MatchesLimitation(ex, limitation) {
+			continue
+		}
+		if limitation.Severity == LimitationSeverityAvoid {
+			return LimitationSeverityAvoid
+		}
+		severity = LimitationSeverityLimit
+	}
+	return severity
+}
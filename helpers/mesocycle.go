@@ -0,0 +1,171 @@
+package helpers
+
+import (
+	"math"
+	"strings"
+	"time"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+const (
+	PhaseAccumulation    = "accumulation"
+	PhaseIntensification = "intensification"
+	PhaseDeload          = "deload"
+
+	DefaultMesocycleWeeks = 4
+	MinMesocycleWeeks     = 3
+	MaxMesocycleWeeks     = 8
+)
+
+// phaseAdjustment scales the base prescription for one phase.
+type phaseAdjustment struct {
+	Sets float64
+	Reps float64
+	Load float64
+}
+
+// Accumulation trains the base prescription. Intensification trades reps for load,
+// and deload halves the sets at a lighter load so the next mesocycle starts recovered.
+var phaseAdjustments = map[string]phaseAdjustment{
+	PhaseAccumulation:    {Sets: 1, Reps: 1, Load: 1},
+	PhaseIntensification: {Sets: 1, Reps: 0.8, Load: 1.075},
+	PhaseDeload:          {Sets: 0.5, Reps: 1, Load: 0.85},
+}
+
+// MesocyclePhases lays out a mesocycle of weeks weeks: accumulation weeks, one intensification
+// week before the end (skipped for beginners and short cycles), and a closing deload.
+func MesocyclePhases(weeks int, intensity string) []string {
+	if weeks < MinMesocycleWeeks {
+		weeks = MinMesocycleWeeks
+	}
+
+	phases := make([]string, weeks)
+	for i := range phases {
+		phases[i] = PhaseAccumulation
+	}
+	if weeks >= 4 && !strings.EqualFold(intensity, "Beginner") {
+		phases[weeks-2] = PhaseIntensification
+	}
+	phases[weeks-1] = PhaseDeload
+	return phases
+}
+
+// BuildPlanWeeks returns unsaved mesocycle weeks for a new plan.
+func BuildPlanWeeks(weeks int, intensity string) []models.WorkoutPlanWeek {
+	phases := MesocyclePhases(weeks, intensity)
+	result := make([]models.WorkoutPlanWeek, len(phases))
+	for i, phase := range phases {
+		result[i] = models.WorkoutPlanWeek{WeekNumber: i + 1, Phase: phase}
+	}
+	return result
+}
+
+// PlanWeekForDate finds the mesocycle week a date falls in. Weeks run Monday to Sunday, week 1
+// being the week of the plan start date, and the mesocycle repeats once its last week is over.
+// Plans generated before mesocycles have no weeks and report false.
+func PlanWeekForDate(plan models.WorkoutPlan, date time.Time) (models.WorkoutPlanWeek, time.Time, bool) {
+	if len(plan.Weeks) == 0 {
+		return models.WorkoutPlanWeek{}, time.Time{}, false
+	}
+
+	start := mondayOf(PlanStartDate(plan))
+	if date.Before(start) {
+		date = start
+	}
+	weekIndex := int(date.Sub(start).Hours()/24) / 7
+	weekStart := start.AddDate(0, 0, weekIndex*7)
+
+	week, ok := planWeekByNumber(plan.Weeks, weekIndex%len(plan.Weeks)+1)
+	return week, weekStart, ok
+}
+
+func planWeekByNumber(weeks []models.WorkoutPlanWeek, number int) (models.WorkoutPlanWeek, bool) {
+	for _, week := range weeks {
+		if week.WeekNumber == number {
+			return week, true
+		}
+	}
+	return models.WorkoutPlanWeek{}, false
+}
+
+// AdjustPrescription applies a phase to a base prescription. Unknown phases leave it unchanged.
+func AdjustPrescription(base Prescription, phase string) Prescription {
+	adjustment, ok := phaseAdjustments[phase]
+	if !ok {
+		return base
+	}
+
+	adjusted := base
+	if base.Sets > 0 {
+		adjusted.Sets = int(math.Max(1, math.Round(float64(base.Sets)*adjustment.Sets)))
+	}
+	if base.Reps > 0 {
+		adjusted.Reps = int(math.Max(1, math.Round(float64(base.Reps)*adjustment.Reps)))
+	}
+	adjusted.Load = math.Round(base.Load*adjustment.Load*2) / 2
+	return adjusted
+}
+
+// AdjustExercise returns ex with its sets, reps and load adjusted for phase. Continuous cardio
+// has its duration and distance scaled like sets.
+func AdjustExercise(ex models.WorkoutPlanExercise, phase string) models.WorkoutPlanExercise {
+	adjusted := AdjustPrescription(Prescription{Reps: ex.Reps, Sets: ex.Sets, Load: ex.Load}, phase)
+	ex.Reps, ex.Sets, ex.Load = adjusted.Reps, adjusted.Sets, adjusted.Load
+	if adjustment, ok := phaseAdjustments[phase]; ok && ex.Mode == models.ExerciseModeDuration {
+		ex.DurationSeconds = int(math.Round(float64(ex.DurationSeconds) * adjustment.Sets))
+		ex.DistanceMeters = int(math.Round(float64(ex.DistanceMeters)*adjustment.Sets/100)) * 100
+	}
+	return ex
+}
+
+// AdjustDays returns a copy of days with every exercise adjusted for phase.
+func AdjustDays(days []models.WorkoutPlanDay, phase string) []models.WorkoutPlanDay {
+	result := make([]models.WorkoutPlanDay, len(days))
+	for i, day := range days {
+		exercises := make([]models.WorkoutPlanExercise, len(day.Exercises))
+		for j, ex := range day.Exercises {
+			exercises[j] = AdjustExercise(ex, phase)
+		}
+		day.Exercises = exercises
+		result[i] = day
+	}
+	return result
+}
+
+// ModifiesPrescription reports whether phase changes the base prescription at all.
+func ModifiesPrescription(phase string) bool {
+	adjustment, ok := phaseAdjustments[phase]
+	return ok && adjustment != phaseAdjustments[PhaseAccumulation]
+}
+
+// PlanWeekDTOForDate describes the mesocycle week date falls in, or nil for plans without a mesocycle.
+func PlanWeekDTOForDate(plan models.WorkoutPlan, date time.Time) *dto.PlanWeek {
+	week, weekStart, ok := PlanWeekForDate(plan, date)
+	if !ok {
+		return nil
+	}
+	return &dto.PlanWeek{WeekNumber: week.WeekNumber, Phase: week.Phase, StartDate: weekStart.Format(DateLayout)}
+}
+
+// BasePrescription returns the unadjusted prescription of ex when phase changes it, for display
+// next to the adjusted values.
+func BasePrescription(ex models.WorkoutPlanExercise, phase string) *dto.PrescriptionDTO {
+	if !ModifiesPrescription(phase) {
+		return nil
+	}
+	return &dto.PrescriptionDTO{Reps: ex.Reps, Sets: ex.Sets, Load: ex.Load}
+}
+
+// BuildPlanWeekDTOs lists the mesocycle weeks of a plan.
+func BuildPlanWeekDTOs(weeks []models.WorkoutPlanWeek) []dto.PlanWeek {
+	result := make([]dto.PlanWeek, 0, len(weeks))
+	for _, week := range weeks {
+		result = append(result, dto.PlanWeek{WeekNumber: week.WeekNumber, Phase: week.Phase})
+	}
+	return result
+}
+
+func mondayOf(date time.Time) time.Time {
+	return date.AddDate(0, 0, 1-DayNumberForDate(date))
+}
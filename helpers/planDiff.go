@@ -0,0 +1,193 @@
+package helpers
+
+import (
+	"sort"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+// DiffPlanDays compares the days of the current plan with a proposed one.
+// A day going from Rest to training (or back) is reported as added/removed; within a
+// training day exercises are matched by ExerciseID, and leftovers are paired by order as swaps.
+// Matched exercises are reported when any part of their prescription differs: sets, reps, load,
+// mode and cardio dose, rest, tempo, effort targets, or the superset or circuit they belong to.
+func DiffPlanDays(current, proposed []models.WorkoutPlanDay, exMap map[uint64]*models.Exercise) dto.PlanDiff {
+	diff := dto.PlanDiff{
+		DaysAdded:       []dto.DayChange{},
+		DaysRemoved:     []dto.DayChange{},
+		FocusChanges:    []dto.FocusChange{},
+		ExerciseChanges: []dto.ExerciseChange{},
+	}
+
+	currentByNumber := trainingDaysByNumber(current)
+	proposedByNumber := trainingDaysByNumber(proposed)
+
+	for dayNum := 1; dayNum <= 7; dayNum++ {
+		before, hadDay := currentByNumber[dayNum]
+		after, hasDay := proposedByNumber[dayNum]
+
+		switch {
+		case !hadDay && hasDay:
+			diff.DaysAdded = append(diff.DaysAdded, dayChange(after, exMap))
+		case hadDay && !hasDay:
+			diff.DaysRemoved = append(diff.DaysRemoved, dayChange(before, exMap))
+		case hadDay && hasDay:
+			if before.Focus != after.Focus {
+				diff.FocusChanges = append(diff.FocusChanges, dto.FocusChange{
+					DayNumber: dayNum,
+					Weekday:   WeekdayName(dayNum),
+					From:      before.Focus,
+					To:        after.Focus,
+				})
+			}
+			diff.ExerciseChanges = append(diff.ExerciseChanges, diffDayExercises(dayNum, before, after, exMap)...)
+		}
+	}
+
+	diff.HasChanges = len(diff.DaysAdded) > 0 || len(diff.DaysRemoved) > 0 ||
+		len(diff.FocusChanges) > 0 || len(diff.ExerciseChanges) > 0
+	return diff
+}
+
+func trainingDaysByNumber(days []models.WorkoutPlanDay) map[int]models.WorkoutPlanDay {
+	result := make(map[int]models.WorkoutPlanDay)
+	for _, day := range days {
+		if models.IsRestDay(day) {
+			continue
+		}
+		result[day.DayNumber] = day
+	}
+	return result
+}
+
+func dayChange(day models.WorkoutPlanDay, exMap map[uint64]*models.Exercise) dto.DayChange {
+	change := dto.DayChange{
+		DayNumber: day.DayNumber,
+		Weekday:   WeekdayName(day.DayNumber),
+		Focus:     day.Focus,
+	}
+	for _, ex := range sortedPlanExercises(day.Exercises) {
+		change.Exercises = append(change.Exercises, exerciseSnapshot(ex, day, exMap))
+	}
+	return change
+}
+
+func diffDayExercises(dayNum int, beforeDay, afterDay models.WorkoutPlanDay, exMap map[uint64]*models.Exercise) []dto.ExerciseChange {
+	changes := []dto.ExerciseChange{}
+
+	afterByExercise := make(map[uint64]models.WorkoutPlanExercise)
+	for _, ex := range afterDay.Exercises {
+		afterByExercise[ex.ExerciseID] = ex
+	}
+
+	var removed []models.WorkoutPlanExercise
+	matched := make(map[uint64]bool)
+	for _, old := range sortedPlanExercises(beforeDay.Exercises) {
+		next, ok := afterByExercise[old.ExerciseID]
+		if !ok {
+			removed = append(removed, old)
+			continue
+		}
+		matched[old.ExerciseID] = true
+		b, a := exerciseSnapshot(old, beforeDay, exMap), exerciseSnapshot(next, afterDay, exMap)
+		if prescriptionChanged(b, a) {
+			changes = append(changes, dto.ExerciseChange{DayNumber: dayNum, Type: dto.ExerciseChangePrescription, Before: &b, After: &a})
+		}
+	}
+
+	var added []models.WorkoutPlanExercise
+	for _, ex := range sortedPlanExercises(afterDay.Exercises) {
+		if !matched[ex.ExerciseID] {
+			added = append(added, ex)
+		}
+	}
+
+	for len(removed) > 0 && len(added) > 0 {
+		b, a := exerciseSnapshot(removed[0], beforeDay, exMap), exerciseSnapshot(added[0], afterDay, exMap)
+		changes = append(changes, dto.ExerciseChange{DayNumber: dayNum, Type: dto.ExerciseChangeSwapped, Before: &b, After: &a})
+		removed, added = removed[1:], added[1:]
+	}
+	for _, ex := range removed {
+		b := exerciseSnapshot(ex, beforeDay, exMap)
+		changes = append(changes, dto.ExerciseChange{DayNumber: dayNum, Type: dto.ExerciseChangeRemoved, Before: &b})
+	}
+	for _, ex := range added {
+		a := exerciseSnapshot(ex, afterDay, exMap)
+		changes = append(changes, dto.ExerciseChange{DayNumber: dayNum, Type: dto.ExerciseChangeAdded, After: &a})
+	}
+
+	return changes
+}
+
+// prescriptionChanged reports whether two snapshots of the same exercise are prescribed differently.
+func prescriptionChanged(before, after dto.ExerciseSnapshot) bool {
+	if len(before.GroupedWith) != len(after.GroupedWith) {
+		return true
+	}
+	for i := range before.GroupedWith {
+		if before.GroupedWith[i] != after.GroupedWith[i] {
+			return true
+		}
+	}
+	return before.Mode != after.Mode ||
+		before.Reps != after.Reps ||
+		before.Sets != after.Sets ||
+		before.Load != after.Load ||
+		before.RestSeconds != after.RestSeconds ||
+		before.Tempo != after.Tempo ||
+		before.TargetRPE != after.TargetRPE ||
+		before.TargetRIR != after.TargetRIR ||
+		before.DurationSeconds != after.DurationSeconds ||
+		before.DistanceMeters != after.DistanceMeters ||
+		before.WorkSeconds != after.WorkSeconds ||
+		before.HeartRateZone != after.HeartRateZone ||
+		before.GroupType != after.GroupType
+}
+
+func sortedPlanExercises(exercises []models.WorkoutPlanExercise) []models.WorkoutPlanExercise {
+	sorted := append([]models.WorkoutPlanExercise(nil), exercises...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Order < sorted[j].Order })
+	return sorted
+}
+
+// exerciseSnapshot captures the prescription of ex on day, including the type of its group and
+// the exercises it is grouped with (sorted by ID, so group renumbering is not a change).
+func exerciseSnapshot(ex models.WorkoutPlanExercise, day models.WorkoutPlanDay, exMap map[uint64]*models.Exercise) dto.ExerciseSnapshot {
+	mode := ex.Mode
+	if IsRepsMode(mode) {
+		mode = models.ExerciseModeReps
+	}
+	snapshot := dto.ExerciseSnapshot{
+		ExerciseID:      ex.ExerciseID,
+		Mode:            mode,
+		Reps:            ex.Reps,
+		Sets:            ex.Sets,
+		Load:            ex.Load,
+		RestSeconds:     ex.RestSeconds,
+		Tempo:           ex.Tempo,
+		TargetRPE:       ex.TargetRPE,
+		TargetRIR:       ex.TargetRIR,
+		DurationSeconds: ex.DurationSeconds,
+		DistanceMeters:  ex.DistanceMeters,
+		WorkSeconds:     ex.WorkSeconds,
+		HeartRateZone:   ex.HeartRateZone,
+	}
+	if detail, ok := exMap[ex.ExerciseID]; ok {
+		snapshot.Name = detail.Name
+	}
+
+	if ex.GroupNumber > 0 {
+		for _, group := range day.Groups {
+			if group.GroupNumber == ex.GroupNumber {
+				snapshot.GroupType = group.Type
+			}
+		}
+		for _, other := range day.Exercises {
+			if other.GroupNumber == ex.GroupNumber && other.ExerciseID != ex.ExerciseID {
+				snapshot.GroupedWith = append(snapshot.GroupedWith, other.ExerciseID)
+			}
+		}
+		sort.Slice(snapshot.GroupedWith, func(i, j int) bool { return snapshot.GroupedWith[i] < snapshot.GroupedWith[j] })
+	}
+	return snapshot
+}
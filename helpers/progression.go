@@ -0,0 +1,149 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"wellnesspath/models"
+)
+
+const (
+	DefaultSetsPerExercise = 3
+	MaxSetsPerExercise     = 5
+	maxProgressionRPE      = 9.0
+)
+
+type Prescription struct {
+	Reps int
+	Sets int
+	Load float64
+}
+
+type ProgressionResult struct {
+	Proposed Prescription
+	Rule     string
+	Reason   string
+}
+
+// ProposeProgression applies the goal-specific progression rule to one plan exercise,
+// using the sets logged for it in the most recent completed session.
+// baseReps is the bottom of the rep range as returned by DetermineReps.
+func ProposeProgression(goal string, baseReps int, current Prescription, logged []models.WorkoutSessionSet) (ProgressionResult, bool) {
+	if len(logged) == 0 {
+		return ProgressionResult{}, false
+	}
+
+	next := current
+	workingLoad := 0.0
+	for _, set := range logged {
+		workingLoad = math.Max(workingLoad, set.Load)
+	}
+	if next.Load == 0 && workingLoad > 0 {
+		next.Load = workingLoad
+	}
+
+	if !sessionSucceeded(current, logged) {
+		if next != current {
+			return ProgressionResult{
+				Proposed: next,
+				Rule:     "Baseline",
+				Reason:   fmt.Sprintf("Use the %.1f kg you lifted as the prescribed load", next.Load),
+			}, true
+		}
+		return ProgressionResult{}, false
+	}
+
+	var rule, reason string
+	switch strings.ToLower(goal) {
+	case "fat loss":
+		rule = "Density"
+		next, reason = progressDensity(next, baseReps)
+	case "stamina":
+		rule = "Volume"
+		next, reason = progressVolume(next, baseReps)
+	default:
+		rule = "Double Progression"
+		next, reason = progressDouble(next, baseReps)
+	}
+
+	if next == current {
+		return ProgressionResult{}, false
+	}
+	return ProgressionResult{Proposed: next, Rule: rule, Reason: reason}, true
+}
+
+// sessionSucceeded reports whether every prescribed set was completed at the prescribed reps without grinding.
+func sessionSucceeded(current Prescription, logged []models.WorkoutSessionSet) bool {
+	if len(logged) < current.Sets {
+		return false
+	}
+	for _, set := range logged {
+		if set.Reps < current.Reps {
+			return false
+		}
+		if set.RPE > maxProgressionRPE {
+			return false
+		}
+	}
+	return true
+}
+
+// Muscle Gain / General Fitness: add reps up to the top of the range, then add load and start again at the bottom.
+func progressDouble(p Prescription, baseReps int) (Prescription, string) {
+	top := baseReps + 4
+	if p.Reps < top {
+		p.Reps++
+		return p, fmt.Sprintf("All sets completed; add a rep (range %d–%d)", baseReps, top)
+	}
+	if p.Load > 0 {
+		p.Load += LoadIncrement(p.Load)
+		p.Reps = baseReps
+		return p, fmt.Sprintf("Top of the %d–%d range reached; add load and return to %d reps", baseReps, top, baseReps)
+	}
+	if p.Sets < MaxSetsPerExercise {
+		p.Sets++
+		p.Reps = baseReps
+		return p, "Top of the range reached without external load; add a set"
+	}
+	return p, ""
+}
+
+// Fat Loss: fit more work into the session at the same load, first through reps, then sets.
+func progressDensity(p Prescription, baseReps int) (Prescription, string) {
+	top := baseReps + 6
+	if p.Reps < top {
+		p.Reps = int(math.Min(float64(p.Reps+2), float64(top)))
+		return p, "All sets completed; add reps at the same load"
+	}
+	if p.Sets < MaxSetsPerExercise {
+		p.Sets++
+		p.Reps = baseReps
+		return p, "Rep ceiling reached; add a set"
+	}
+	if p.Load > 0 {
+		p.Load += LoadIncrement(p.Load)
+		p.Reps = baseReps
+		p.Sets = DefaultSetsPerExercise
+		return p, "Rep and set ceilings reached; add load and reset volume"
+	}
+	return p, ""
+}
+
+// Stamina: grow total volume, sets first, then reps.
+func progressVolume(p Prescription, baseReps int) (Prescription, string) {
+	if p.Sets < MaxSetsPerExercise {
+		p.Sets++
+		return p, "All sets completed; add a set"
+	}
+	top := baseReps + 10
+	if p.Reps < top {
+		p.Reps = int(math.Min(float64(p.Reps+2), float64(top)))
+		return p, "Set ceiling reached; add reps"
+	}
+	return p, ""
+}
+
+// LoadIncrement returns roughly 5% of the load, rounded to the nearest 2.5 kg plate step.
+func LoadIncrement(load float64) float64 {
+	return math.Max(2.5, math.Round(load*0.05/2.5)*2.5)
+}
@@ -0,0 +1,75 @@
+package helpers
+
+import (
+	"sort"
+	"time"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+const (
+	DefaultScheduleWeeks = 4
+	MaxScheduleWeeks     = 52
+	MaxScheduleRangeDays = 366
+)
+
+// BuildSchedule expands the weekly plan into dated exercises for every day in [from, to], adjusting
+// each date's prescription for the mesocycle week it falls in. Dates before the plan start date are
+// skipped; all dates are civil dates (see CivilDate).
+func BuildSchedule(plan models.WorkoutPlan, exMap map[uint64]*models.Exercise, from, to time.Time) []dto.ScheduledExercise {
+	dayByNumber := make(map[int]models.WorkoutPlanDay)
+	for _, day := range plan.Days {
+		exercises := append([]models.WorkoutPlanExercise(nil), day.Exercises...)
+		sort.Slice(exercises, func(i, j int) bool { return exercises[i].Order < exercises[j].Order })
+		day.Exercises = exercises
+		dayByNumber[day.DayNumber] = day
+	}
+
+	if start := PlanStartDate(plan); from.Before(start) {
+		from = start
+	}
+
+	schedule := []dto.ScheduledExercise{}
+	for date := from; !date.After(to); date = date.AddDate(0, 0, 1) {
+		day, ok := dayByNumber[DayNumberForDate(date)]
+		if !ok {
+			continue
+		}
+		week, _, hasWeek := PlanWeekForDate(plan, date)
+
+		for _, ex := range day.Exercises {
+			detail, ok := exMap[ex.ExerciseID]
+			if !ok {
+				continue
+			}
+			if hasWeek {
+				ex = AdjustExercise(ex, week.Phase)
+			}
+			schedule = append(schedule, dto.ScheduledExercise{
+				DayNumber:       day.DayNumber,
+				Date:            date.Format(DateLayout),
+				Focus:           day.Focus,
+				WeekNumber:      week.WeekNumber,
+				Phase:           week.Phase,
+				PlanExerciseID:  ex.ID,
+				ExerciseID:      ex.ExerciseID,
+				Exercise:        detail.Name,
+				Mode:            ex.Mode,
+				Reps:            ex.Reps,
+				Sets:            ex.Sets,
+				Load:            ex.Load,
+				RestSeconds:     ex.RestSeconds,
+				Tempo:           ex.Tempo,
+				TargetRPE:       ex.TargetRPE,
+				TargetRIR:       ex.TargetRIR,
+				DurationSeconds: ex.DurationSeconds,
+				DistanceMeters:  ex.DistanceMeters,
+				TargetPace:      ex.TargetPace,
+				HeartRateZone:   ex.HeartRateZone,
+				WorkSeconds:     ex.WorkSeconds,
+			})
+		}
+	}
+
+	return schedule
+}
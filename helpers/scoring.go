@@ -0,0 +1,249 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"math"
+	"os"
+	"strings"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+// SelectionWeights controls how much each criterion contributes to an exercise's score.
+// Every criterion is scored between 0 and 1 before weighting.
+type SelectionWeights struct {
+	GoalTag    float64 `json:"goalTag"`
+	Difficulty float64 `json:"difficulty"`
+	Equipment  float64 `json:"equipment"`
+	Compound   float64 `json:"compound"`
+	Rating     float64 `json:"rating"`
+	Coverage   float64 `json:"coverage"`
+	Volume     float64 `json:"volume"`
+	Favourite  float64 `json:"favourite"`
+}
+
+const defaultWeightsKey = "default"
+
+// limitedExercisePenalty is subtracted from exercises that match a "limit" limitation, so they
+// only win a slot over clearly less suitable unrestricted exercises.
+const limitedExercisePenalty = 5
+
+var selectionWeights = map[string]SelectionWeights{
+	defaultWeightsKey: {GoalTag: 2, Difficulty: 2, Equipment: 1, Compound: 1, Rating: 1, Coverage: 3, Volume: 2, Favourite: 2},
+	"muscle gain":     {GoalTag: 3, Difficulty: 2, Equipment: 1, Compound: 1.5, Rating: 1, Coverage: 3, Volume: 2.5, Favourite: 2},
+	"fat loss":        {GoalTag: 3, Difficulty: 2, Equipment: 0.5, Compound: 2, Rating: 1, Coverage: 2, Volume: 1.5, Favourite: 2},
+	"stamina":         {GoalTag: 3, Difficulty: 2, Equipment: 0.5, Compound: 1, Rating: 1, Coverage: 2, Volume: 1.5, Favourite: 2},
+}
+
+// LoadSelectionWeights overrides the built-in weights with a JSON file keyed by goal
+// (or "default"), e.g. {"Muscle Gain": {"goalTag": 3, "coverage": 4, ...}}.
+// Goals missing from the file keep their built-in weights. An empty path is a no-op.
+func LoadSelectionWeights(path string) error {
+	if path == "" {
+		return nil
+	}
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read selection weights: %w", err)
+	}
+
+	var overrides map[string]SelectionWeights
+	if err := json.Unmarshal(data, &overrides); err != nil {
+		return fmt.Errorf("failed to parse selection weights: %w", err)
+	}
+
+	for goal, weights := range overrides {
+		if weights.GoalTag < 0 || weights.Difficulty < 0 || weights.Equipment < 0 ||
+			weights.Compound < 0 || weights.Rating < 0 || weights.Coverage < 0 || weights.Volume < 0 || weights.Favourite < 0 {
+			return fmt.Errorf("selection weights for %q must not be negative", goal)
+		}
+		selectionWeights[strings.ToLower(goal)] = weights
+	}
+	return nil
+}
+
+// WeightsForGoal returns the weights used when generating a plan for goal.
+func WeightsForGoal(goal string) SelectionWeights {
+	if weights, ok := selectionWeights[strings.ToLower(goal)]; ok {
+		return weights
+	}
+	return selectionWeights[defaultWeightsKey]
+}
+
+// RankedExercise is an exercise picked by RankExercises together with its score.
+type RankedExercise struct {
+	Exercise  models.Exercise
+	Score     float64
+	Breakdown dto.ScoreBreakdown
+	// Relaxed is set when the diversity limits had to be lifted to fill the day.
+	Relaxed bool
+	// Favourite is set for exercises the user marked as favourites.
+	Favourite bool
+}
+
+// RankExercises picks up to count exercises from candidates, highest score first, skipping
+// excluded IDs. Coverage is rescored after every pick so the day spreads across validParts,
+// and no body part gets more than its share of the day until every other option is used up.
+// volume (optional) carries the sets earlier days already gave each body part, and favourites
+// (optional) the exercises the user prefers, which score higher.
+// Exercises the profile's limitations avoid are never picked; limited ones score lower and
+// at most one is picked per day before the diversity limits are lifted.
+// Ties keep the candidates' order, so shuffling them beforehand decides between equal scores.
+func RankExercises(candidates []models.Exercise, profile *models.Profile, validParts []string, count int, excluded map[uint64]bool, volume *VolumePlanner, favourites map[uint64]bool) []RankedExercise {
+	weights := WeightsForGoal(profile.Goal)
+	limitations := DecodeLimitations(profile.LimitationsJSON)
+
+	pool := make([]models.Exercise, 0, len(candidates))
+	limited := map[uint64]bool{}
+	for _, ex := range candidates {
+		if excluded[ex.ID] {
+			continue
+		}
+		switch LimitationSeverity(ex, limitations) {
+		case LimitationSeverityAvoid:
+			continue
+		case LimitationSeverityLimit:
+			limited[ex.ID] = true
+		}
+		pool = append(pool, ex)
+	}
+
+	partsInPool := map[string]bool{}
+	for _, ex := range pool {
+		partsInPool[strings.ToLower(ex.BodyPart)] = true
+	}
+	maxPerPart := 1
+	if len(partsInPool) > 0 {
+		maxPerPart = int(math.Ceil(float64(count) / float64(len(partsInPool))))
+	}
+
+	var selected []RankedExercise
+	picked := map[uint64]bool{}
+	partCounts := map[string]int{}
+	limitedPicks := 0
+
+	for _, relaxed := range []bool{false, true} {
+		for len(selected) < count {
+			bestIdx := -1
+			var best dto.ScoreBreakdown
+			var bestScore float64
+
+			for i, ex := range pool {
+				if picked[ex.ID] {
+					continue
+				}
+				part := strings.ToLower(ex.BodyPart)
+				if !relaxed && (partCounts[part] >= maxPerPart || (limited[ex.ID] && limitedPicks > 0)) {
+					continue
+				}
+
+				breakdown := scoreExercise(ex, profile, validParts, partCounts, volume, weights, limited[ex.ID], favourites[ex.ID])
+				score := breakdown.Total()
+				if bestIdx == -1 || score > bestScore {
+					bestIdx, best, bestScore = i, breakdown, score
+				}
+			}
+
+			if bestIdx == -1 {
+				break
+			}
+
+			ex := pool[bestIdx]
+			picked[ex.ID] = true
+			partCounts[strings.ToLower(ex.BodyPart)]++
+			if limited[ex.ID] {
+				limitedPicks++
+			}
+			selected = append(selected, RankedExercise{
+				Exercise:  ex,
+				Score:     math.Round(bestScore*100) / 100,
+				Breakdown: best,
+				Relaxed:   relaxed,
+				Favourite: favourites[ex.ID],
+			})
+		}
+	}
+
+	return selected
+}
+
+func scoreExercise(ex models.Exercise, profile *models.Profile, validParts []string, partCounts map[string]int, volume *VolumePlanner, weights SelectionWeights, limited bool, favourite bool) dto.ScoreBreakdown {
+	pendingSets := partCounts[strings.ToLower(ex.BodyPart)] * DefaultSetsPerExercise
+
+	limitation := 0.0
+	if limited {
+		limitation = -limitedExercisePenalty
+	}
+	favouriteScore := 0.0
+	if favourite {
+		favouriteScore = 1
+	}
+
+	return dto.ScoreBreakdown{
+		GoalTag:    weights.GoalTag * goalTagScore(ex.GoalTag, profile.Goal),
+		Difficulty: weights.Difficulty * difficultyScore(ex.Difficulty, profile.Intensity),
+		Equipment:  weights.Equipment * equipmentScore(ex.Equipment),
+		Compound:   weights.Compound * compoundScore(ex.ExerciseType),
+		Rating:     weights.Rating * ratingScore(ex.Rating),
+		Coverage:   weights.Coverage * coverageScore(ex.BodyPart, validParts, partCounts),
+		Volume:     weights.Volume * volume.need(ex.BodyPart, pendingSets),
+		Favourite:  weights.Favourite * favouriteScore,
+		Limitation: limitation,
+	}
+}
+
+func goalTagScore(tag, goal string) float64 {
+	switch {
+	case strings.EqualFold(tag, goal):
+		return 1
+	case strings.EqualFold(tag, "General Fitness"):
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+// difficultyScore falls off with the distance between the exercise and the user's level,
+// twice as fast for exercises above the user's level as below it.
+func difficultyScore(difficulty, intensity string) float64 {
+	exRank, ok := difficultyRank[strings.ToLower(difficulty)]
+	userRank, userOK := difficultyRank[strings.ToLower(intensity)]
+	if !ok || !userOK {
+		return 0.5
+	}
+
+	distance := float64(exRank - userRank)
+	if distance > 0 {
+		return math.Max(0, 1-0.5*distance)
+	}
+	return 1 + 0.25*distance
+}
+
+// equipmentScore prefers exercises that use the user's equipment over bodyweight ones.
+func equipmentScore(equipment string) float64 {
+	if strings.EqualFold(equipment, "Body Only") {
+		return 0.5
+	}
+	return 1
+}
+
+func compoundScore(exerciseType string) float64 {
+	if strings.EqualFold(exerciseType, "Compound") {
+		return 1
+	}
+	return 0
+}
+
+func ratingScore(rating float64) float64 {
+	return math.Min(math.Max(rating/10, 0), 1)
+}
+
+// coverageScore rewards body parts the day has not trained yet; parts outside the focus score 0.
+func coverageScore(bodyPart string, validParts []string, partCounts map[string]int) float64 {
+	if !Contains(validParts, bodyPart) {
+		return 0
+	}
+	return 1 / float64(1+partCounts[strings.ToLower(bodyPart)])
+}
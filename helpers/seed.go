@@ -0,0 +1,46 @@
+package helpers
+
+import (
+	crand "crypto/rand"
+	"encoding/binary"
+	"math/rand"
+	"strings"
+	"time"
+	"wellnesspath/models"
+)
+
+// MaxPlanSeed keeps seeds within the range JavaScript numbers represent exactly.
+const MaxPlanSeed = 1<<31 - 1
+
+// NewPlanSeed returns a random seed in [1, MaxPlanSeed].
+func NewPlanSeed() int64 {
+	var b [8]byte
+	if _, err := crand.Read(b[:]); err != nil {
+		return time.Now().UnixNano()%MaxPlanSeed + 1
+	}
+	return int64(binary.BigEndian.Uint64(b[:])%MaxPlanSeed) + 1
+}
+
+// NewPlanRand returns the random source used for one plan generation run.
+// The same seed always yields the same sequence, which is what makes plans reproducible.
+func NewPlanRand(seed int64) *rand.Rand {
+	return rand.New(rand.NewSource(seed))
+}
+
+// ShuffleExercises returns a shuffled copy of exercises. Equipment-based exercises keep
+// their priority over "Body Only" ones, matching the order GetExercisesByGoalAndEquipment returns.
+func ShuffleExercises(rng *rand.Rand, exercises []models.Exercise) []models.Exercise {
+	var equipped, bodyOnly []models.Exercise
+	for _, ex := range exercises {
+		if strings.EqualFold(ex.Equipment, "Body Only") {
+			bodyOnly = append(bodyOnly, ex)
+		} else {
+			equipped = append(equipped, ex)
+		}
+	}
+
+	rng.Shuffle(len(equipped), func(i, j int) { equipped[i], equipped[j] = equipped[j], equipped[i] })
+	rng.Shuffle(len(bodyOnly), func(i, j int) { bodyOnly[i], bodyOnly[j] = bodyOnly[j], bodyOnly[i] })
+
+	return append(equipped, bodyOnly...)
+}
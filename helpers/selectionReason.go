@@ -0,0 +1,141 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+const (
+	SelectionTierRanked           = "ranked"
+	SelectionTierDiversityRelaxed = "diversity_relaxed"
+	SelectionTierAnyExercise      = "any_exercise"
+	SelectionTierUserReplacement  = "user_replacement"
+)
+
+const (
+	CoverageNewBodyPart  = "new_body_part"
+	CoverageRepeated     = "repeated_body_part"
+	CoverageOutsideFocus = "outside_focus"
+)
+
+var difficultyRank = map[string]int{
+	"beginner":     1,
+	"intermediate": 2,
+	"advanced":     3,
+}
+
+// ExplainSelection describes why a ranked exercise was picked for a day with the given focus.
+// focusFallback is set when no exercise matched the focus and the whole pool was ranked instead;
+// coveredParts holds the body parts already trained by earlier exercises of the same day.
+func ExplainSelection(pick RankedExercise, focusFallback bool, profile *models.Profile, focus FocusDefinition, coveredParts map[string]bool) dto.SelectionReason {
+	ex := pick.Exercise
+	breakdown := pick.Breakdown
+
+	tier := SelectionTierRanked
+	switch {
+	case focusFallback:
+		tier = SelectionTierAnyExercise
+	case pick.Relaxed:
+		tier = SelectionTierDiversityRelaxed
+	}
+
+	reason := dto.SelectionReason{
+		Tier:           tier,
+		Difficulty:     ex.Difficulty,
+		BodyPart:       ex.BodyPart,
+		FallbackUsed:   tier != SelectionTierRanked,
+		Favourite:      pick.Favourite,
+		Score:          pick.Score,
+		ScoreBreakdown: &breakdown,
+	}
+
+	if strings.EqualFold(ex.GoalTag, profile.Goal) || strings.EqualFold(ex.GoalTag, "General Fitness") {
+		reason.MatchedGoalTag = ex.GoalTag
+	}
+
+	exRank, ok := difficultyRank[strings.ToLower(ex.Difficulty)]
+	userRank, userOK := difficultyRank[strings.ToLower(profile.Intensity)]
+	reason.DifficultyMatch = ok && userOK && exRank <= userRank
+
+	switch {
+	case !Contains(focus.BodyParts, ex.BodyPart):
+		reason.BodyPartCoverage = CoverageOutsideFocus
+	case coveredParts[strings.ToLower(ex.BodyPart)]:
+		reason.BodyPartCoverage = CoverageRepeated
+	default:
+		reason.BodyPartCoverage = CoverageNewBodyPart
+	}
+
+	reason.Summary = summarizeSelection(reason, profile, focus.Name)
+	return reason
+}
+
+// ReplacementSelectionReason marks an exercise the user chose themselves through a replacement.
+func ReplacementSelectionReason(ex models.Exercise, profile *models.Profile) dto.SelectionReason {
+	reason := dto.SelectionReason{
+		Tier:       SelectionTierUserReplacement,
+		Difficulty: ex.Difficulty,
+		BodyPart:   ex.BodyPart,
+		Summary:    "You chose this exercise as a replacement.",
+	}
+	if strings.EqualFold(ex.GoalTag, profile.Goal) || strings.EqualFold(ex.GoalTag, "General Fitness") {
+		reason.MatchedGoalTag = ex.GoalTag
+	}
+	exRank, ok := difficultyRank[strings.ToLower(ex.Difficulty)]
+	userRank, userOK := difficultyRank[strings.ToLower(profile.Intensity)]
+	reason.DifficultyMatch = ok && userOK && exRank <= userRank
+	return reason
+}
+
+func summarizeSelection(reason dto.SelectionReason, profile *models.Profile, focus string) string {
+	var parts []string
+
+	switch reason.Tier {
+	case SelectionTierRanked:
+		parts = append(parts, fmt.Sprintf("Top-scoring %s exercise for your %s day", reason.BodyPart, focus))
+	case SelectionTierDiversityRelaxed:
+		parts = append(parts, fmt.Sprintf("Added another %s exercise because the other %s body parts ran out of options", reason.BodyPart, focus))
+	case SelectionTierAnyExercise:
+		parts = append(parts, fmt.Sprintf("No exercises in your pool target the %s focus, so this was picked from all exercises matching your goal and equipment", focus))
+	}
+
+	if reason.Favourite {
+		parts = append(parts, "one of your favourites")
+	}
+	if reason.MatchedGoalTag != "" {
+		parts = append(parts, fmt.Sprintf("tagged for %s", reason.MatchedGoalTag))
+	}
+	exRank, exKnown := difficultyRank[strings.ToLower(reason.Difficulty)]
+	userRank, userKnown := difficultyRank[strings.ToLower(profile.Intensity)]
+	switch {
+	case reason.DifficultyMatch:
+		parts = append(parts, fmt.Sprintf("%s difficulty suits your %s level", reason.Difficulty, profile.Intensity))
+	case exKnown && userKnown && exRank > userRank:
+		parts = append(parts, fmt.Sprintf("%s difficulty is above your %s level", reason.Difficulty, profile.Intensity))
+	}
+
+	return strings.Join(parts, "; ") + "."
+}
+
+func EncodeSelectionReason(reason dto.SelectionReason) string {
+	data, err := json.Marshal(reason)
+	if err != nil {
+		return ""
+	}
+	return string(data)
+}
+
+// DecodeSelectionReason returns nil for exercises saved before selection reasons were recorded.
+func DecodeSelectionReason(jsonStr string) *dto.SelectionReason {
+	if jsonStr == "" {
+		return nil
+	}
+	var reason dto.SelectionReason
+	if err := json.Unmarshal([]byte(jsonStr), &reason); err != nil {
+		return nil
+	}
+	return &reason
+}
@@ -0,0 +1,162 @@
+package helpers
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"wellnesspath/models"
+)
+
+const (
+	SplitScopeBuiltIn = "built_in"
+	SplitScopeGlobal  = "global"
+	SplitScopeUser    = "user"
+)
+
+// FocusDefinition is one training day template of a split: its name and the body parts it trains.
+type FocusDefinition struct {
+	Name      string
+	BodyParts []string
+}
+
+// SplitDefinition is a resolved split, built-in or stored in the database, ready for generation.
+type SplitDefinition struct {
+	ID             uint64
+	Name           string
+	Scope          string
+	Focuses        []FocusDefinition
+	MinFrequency   int
+	MaxFrequency   int
+	FullCyclesOnly bool
+}
+
+type builtInSplit struct {
+	name           string
+	focuses        []string
+	minFrequency   int
+	maxFrequency   int
+	fullCyclesOnly bool
+}
+
+var builtInSplits = []builtInSplit{
+	{name: "Push/Pull/Legs", focuses: []string{"Push", "Pull", "Legs"}, minFrequency: 3, maxFrequency: 7, fullCyclesOnly: true},
+	{name: "Upper/Lower", focuses: []string{"Upper", "Lower"}, minFrequency: 2, maxFrequency: 7, fullCyclesOnly: true},
+	{name: "Full Body", focuses: []string{"Full Body"}, minFrequency: 2, maxFrequency: 4},
+	{name: "Bro Split", focuses: []string{"Chest", "Back", "Legs", "Shoulders", "Arms"}, minFrequency: 5, maxFrequency: 5},
+}
+
+// BuiltInSplit returns the built-in split with the given name (case-insensitive).
+// Focus body parts come from GetBodyPartsForFocus.
+func BuiltInSplit(name string) (SplitDefinition, bool) {
+	for _, split := range builtInSplits {
+		if strings.EqualFold(split.name, name) {
+			return split.definition(), true
+		}
+	}
+	return SplitDefinition{}, false
+}
+
+func BuiltInSplits() []SplitDefinition {
+	result := make([]SplitDefinition, 0, len(builtInSplits))
+	for _, split := range builtInSplits {
+		result = append(result, split.definition())
+	}
+	return result
+}
+
+func (b builtInSplit) definition() SplitDefinition {
+	focuses := make([]FocusDefinition, 0, len(b.focuses))
+	for _, name := range b.focuses {
+		focuses = append(focuses, FocusDefinition{Name: name, BodyParts: GetBodyPartsForFocus(name)})
+	}
+	return SplitDefinition{
+		Name:           b.name,
+		Scope:          SplitScopeBuiltIn,
+		Focuses:        focuses,
+		MinFrequency:   b.minFrequency,
+		MaxFrequency:   b.maxFrequency,
+		FullCyclesOnly: b.fullCyclesOnly,
+	}
+}
+
+// FocusSequence repeats the split's focuses in order until frequency training days are filled.
+func (s SplitDefinition) FocusSequence(frequency int) []FocusDefinition {
+	if len(s.Focuses) == 0 {
+		return nil
+	}
+	result := make([]FocusDefinition, 0, frequency)
+	for i := 0; i < frequency; i++ {
+		result = append(result, s.Focuses[i%len(s.Focuses)])
+	}
+	return result
+}
+
+// ValidateFocuses fails when a focus of the split has no body parts, which would otherwise
+// silently fall back to picking from every exercise.
+func (s SplitDefinition) ValidateFocuses() error {
+	if len(s.Focuses) == 0 {
+		return NewBadRequestError(fmt.Sprintf("split %q has no focuses", s.Name))
+	}
+	for _, focus := range s.Focuses {
+		if len(focus.BodyParts) == 0 {
+			return NewBadRequestError(fmt.Sprintf("focus %q of split %q has no mapped body parts; an admin needs to map it first", focus.Name, s.Name))
+		}
+	}
+	return nil
+}
+
+// ValidateFrequency checks that the split can be trained frequency days a week.
+func (s SplitDefinition) ValidateFrequency(frequency int) error {
+	label := s.Name
+	if !strings.HasSuffix(strings.ToLower(label), "split") {
+		label += " split"
+	}
+
+	if s.MinFrequency == s.MaxFrequency && frequency != s.MinFrequency {
+		return fmt.Errorf("%s requires exactly %d workout days", label, s.MinFrequency)
+	}
+	if frequency < s.MinFrequency {
+		return fmt.Errorf("%s requires at least %d workout days", label, s.MinFrequency)
+	}
+	if frequency > s.MaxFrequency {
+		return fmt.Errorf("%s should not exceed %d sessions per week", label, s.MaxFrequency)
+	}
+	if s.FullCyclesOnly && frequency%len(s.Focuses) != 0 {
+		var valid []string
+		for f := s.MinFrequency; f <= s.MaxFrequency; f++ {
+			if f%len(s.Focuses) == 0 {
+				valid = append(valid, fmt.Sprint(f))
+			}
+		}
+		return fmt.Errorf("%s is based on a %d-day cycle; valid frequencies are %s",
+			label, len(s.Focuses), strings.Join(valid, ", "))
+	}
+	return nil
+}
+
+func EncodeBodyParts(bodyParts []string) string {
+	data, err := json.Marshal(bodyParts)
+	if err != nil {
+		return "[]"
+	}
+	return string(data)
+}
+
+func DecodeBodyParts(jsonStr string) []string {
+	var result []string
+	if err := json.Unmarshal([]byte(jsonStr), &result); err != nil {
+		return []string{}
+	}
+	return result
+}
+
+// DayBodyParts returns the body parts a plan day trains. Days generated before splits stored
+// their body parts fall back to the focus registry.
+func DayBodyParts(day models.WorkoutPlanDay) []string {
+	if day.BodyPartsJSON != "" {
+		if parts := DecodeBodyParts(day.BodyPartsJSON); len(parts) > 0 {
+			return parts
+		}
+	}
+	return GetBodyPartsForFocus(day.Focus)
+}
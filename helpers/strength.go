@@ -0,0 +1,167 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"strings"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+const (
+	OneRMFormulaEpley   = "epley"
+	OneRMFormulaBrzycki = "brzycki"
+
+	// DefaultOneRMFormula is used for personal records and when no formula is requested.
+	DefaultOneRMFormula = OneRMFormulaEpley
+	// MaxRepsForOneRM is the highest rep count a set can have and still give a usable estimate.
+	MaxRepsForOneRM = 12
+
+	// minLiftsForIntensityChange is how many reference lifts must meet the next level's standard.
+	minLiftsForIntensityChange = 2
+)
+
+// strengthStandard is a reference lift, the catalogue exercises that count as it, and the
+// one-rep max per intensity level as a multiple of bodyweight.
+type strengthStandard struct {
+	Lift          string
+	ExerciseNames []string
+	Ratios        map[string]float64
+}
+
+var strengthStandards = []strengthStandard{
+	{
+		Lift:          "Squat",
+		ExerciseNames: []string{"Barbell Squat", "Barbell Full Squat"},
+		Ratios:        map[string]float64{"Beginner": 0.75, "Intermediate": 1.25, "Advanced": 1.75},
+	},
+	{
+		Lift:          "Bench Press",
+		ExerciseNames: []string{"Barbell Bench Press - Medium Grip", "Bench Press - Powerlifting", "Wide-grip bench press"},
+		Ratios:        map[string]float64{"Beginner": 0.5, "Intermediate": 1.0, "Advanced": 1.5},
+	},
+	{
+		Lift:          "Deadlift",
+		ExerciseNames: []string{"Barbell Deadlift", "Sumo deadlift"},
+		Ratios:        map[string]float64{"Beginner": 1.0, "Intermediate": 1.5, "Advanced": 2.25},
+	},
+	{
+		Lift:          "Overhead Press",
+		ExerciseNames: []string{"Barbell Shoulder Press", "Military press", "Seated barbell shoulder press"},
+		Ratios:        map[string]float64{"Beginner": 0.35, "Intermediate": 0.65, "Advanced": 0.9},
+	},
+}
+
+func IsValidOneRMFormula(formula string) bool {
+	return formula == OneRMFormulaEpley || formula == OneRMFormulaBrzycki
+}
+
+// EstimateOneRM estimates the one-rep max from a set of reps at load. Sets without load or with
+// more than MaxRepsForOneRM reps give no estimate (0).
+func EstimateOneRM(load float64, reps int, formula string) float64 {
+	if load <= 0 || reps < 1 || reps > MaxRepsForOneRM {
+		return 0
+	}
+	if reps == 1 {
+		return load
+	}
+
+	var estimate float64
+	switch formula {
+	case OneRMFormulaBrzycki:
+		estimate = load * 36 / (37 - float64(reps))
+	default:
+		estimate = load * (1 + float64(reps)/30)
+	}
+	return math.Round(estimate*10) / 10
+}
+
+// BestOneRMSets returns, per exercise, the logged set with the highest estimated one-rep max.
+// Ties go to the earliest set, so the date is when the best was first reached.
+func BestOneRMSets(sets []models.WorkoutSessionSet, formula string) map[uint64]models.WorkoutSessionSet {
+	best := map[uint64]models.WorkoutSessionSet{}
+	bestEstimate := map[uint64]float64{}
+	for _, set := range sets {
+		estimate := EstimateOneRM(set.Load, set.Reps, formula)
+		if estimate == 0 {
+			continue
+		}
+		current, ok := best[set.ExerciseID]
+		if !ok || estimate > bestEstimate[set.ExerciseID] ||
+			(estimate == bestEstimate[set.ExerciseID] && set.CompletedAt.Before(current.CompletedAt)) {
+			best[set.ExerciseID] = set
+			bestEstimate[set.ExerciseID] = estimate
+		}
+	}
+	return best
+}
+
+// RelativeStrength is oneRM as a multiple of bodyWeight, or 0 without a bodyweight.
+func RelativeStrength(oneRM, bodyWeight float64) float64 {
+	if bodyWeight <= 0 {
+		return 0
+	}
+	return math.Round(oneRM/bodyWeight*100) / 100
+}
+
+// AssessStrengthStandards rates the best estimate of each reference lift against its standards.
+// Lifts the user has not logged are listed with their standards only; without a bodyweight no
+// level is given.
+func AssessStrengthStandards(exercises []dto.ExerciseStrength, bodyWeight float64) []dto.LiftStandard {
+	results := make([]dto.LiftStandard, 0, len(strengthStandards))
+	for _, standard := range strengthStandards {
+		result := dto.LiftStandard{Lift: standard.Lift, Standards: standard.Ratios}
+		for _, ex := range exercises {
+			if containsCaseInsensitive(standard.ExerciseNames, ex.Name) && ex.EstimatedOneRM > result.EstimatedOneRM {
+				result.ExerciseID = ex.ExerciseID
+				result.Name = ex.Name
+				result.EstimatedOneRM = ex.EstimatedOneRM
+			}
+		}
+
+		if result.EstimatedOneRM > 0 && bodyWeight > 0 {
+			result.RelativeStrength = RelativeStrength(result.EstimatedOneRM, bodyWeight)
+			for _, level := range allowedIntensities {
+				if result.EstimatedOneRM >= standard.Ratios[level]*bodyWeight {
+					result.Level = level
+					continue
+				}
+				result.NextLevel = level
+				result.NextLevelOneRM = math.Ceil(standard.Ratios[level]*bodyWeight*2) / 2
+				break
+			}
+		}
+		results = append(results, result)
+	}
+	return results
+}
+
+// SuggestIntensity suggests moving a Beginner profile to Intermediate once at least two reference
+// lifts, and at least half of those logged, meet the Intermediate standard. It returns nil otherwise.
+func SuggestIntensity(intensity string, standards []dto.LiftStandard) *dto.IntensitySuggestion {
+	if !strings.EqualFold(intensity, "Beginner") {
+		return nil
+	}
+
+	assessed := 0
+	var met []string
+	for _, lift := range standards {
+		if lift.Level == "" && lift.NextLevel == "" {
+			continue
+		}
+		assessed++
+		if lift.Level == "Intermediate" || lift.Level == "Advanced" {
+			met = append(met, lift.Lift)
+		}
+	}
+	if len(met) < minLiftsForIntensityChange || len(met)*2 < assessed {
+		return nil
+	}
+
+	lifts := strings.Join(met[:len(met)-1], ", ") + " and " + met[len(met)-1]
+	return &dto.IntensitySuggestion{
+		CurrentIntensity:   intensity,
+		SuggestedIntensity: "Intermediate",
+		Reason:             fmt.Sprintf("%s meet the Intermediate strength standard for your bodyweight", lifts),
+	}
+}
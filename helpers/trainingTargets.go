@@ -0,0 +1,181 @@
+package helpers
+
+import (
+	"fmt"
+	"math"
+	"strconv"
+	"strings"
+	"wellnesspath/models"
+)
+
+const (
+	MinRestSeconds = 0
+	MaxRestSeconds = 600
+	MinTargetRPE   = 5.0
+	MaxTargetRPE   = 10.0
+	MaxTargetRIR   = 5
+
+	// exerciseTransitionSeconds is the time to set up the next exercise once the last set is done.
+	exerciseTransitionSeconds = 30
+	// explosiveTempoSeconds is how long an "X" (as fast as possible) tempo phase is counted as.
+	explosiveTempoSeconds = 1
+)
+
+// TrainingTargets is how a plan exercise is performed: rest between sets, lifting tempo
+// (eccentric-pause-concentric-pause, e.g. "3-0-1-0") and target effort as RPE and reps in reserve.
+type TrainingTargets struct {
+	RestSeconds int
+	Tempo       string
+	TargetRPE   float64
+	TargetRIR   int
+}
+
+// goalTargets holds the rest and tempo for a goal, and the target RPE per intensity.
+type goalTargets struct {
+	RestSeconds map[string]int
+	Tempo       string
+	RPE         map[string]float64
+}
+
+var trainingTargetsByGoal = map[string]goalTargets{
+	"muscle gain": {
+		RestSeconds: map[string]int{"beginner": 90, "intermediate": 90, "advanced": 120},
+		Tempo:       "3-0-1-0",
+		RPE:         map[string]float64{"beginner": 7, "intermediate": 8, "advanced": 8.5},
+	},
+	"fat loss": {
+		RestSeconds: map[string]int{"beginner": 45, "intermediate": 40, "advanced": 30},
+		Tempo:       "2-0-1-0",
+		RPE:         map[string]float64{"beginner": 6.5, "intermediate": 7, "advanced": 8},
+	},
+	"stamina": {
+		RestSeconds: map[string]int{"beginner": 45, "intermediate": 30, "advanced": 30},
+		Tempo:       "2-0-2-0",
+		RPE:         map[string]float64{"beginner": 6, "intermediate": 7, "advanced": 7.5},
+	},
+	"general fitness": {
+		RestSeconds: map[string]int{"beginner": 60, "intermediate": 60, "advanced": 75},
+		Tempo:       "2-0-2-0",
+		RPE:         map[string]float64{"beginner": 6.5, "intermediate": 7, "advanced": 8},
+	},
+}
+
+// DefaultTrainingTargets returns the rest, tempo and effort targets for a goal and intensity.
+// Unknown goals use the General Fitness targets and unknown intensities the Intermediate ones.
+func DefaultTrainingTargets(goal, intensity string) TrainingTargets {
+	targets, ok := trainingTargetsByGoal[strings.ToLower(goal)]
+	if !ok {
+		targets = trainingTargetsByGoal["general fitness"]
+	}
+
+	intensity = strings.ToLower(intensity)
+	rest, ok := targets.RestSeconds[intensity]
+	if !ok {
+		rest = targets.RestSeconds["intermediate"]
+	}
+	rpe, ok := targets.RPE[intensity]
+	if !ok {
+		rpe = targets.RPE["intermediate"]
+	}
+
+	return TrainingTargets{
+		RestSeconds: rest,
+		Tempo:       targets.Tempo,
+		TargetRPE:   rpe,
+		TargetRIR:   RIRForRPE(rpe),
+	}
+}
+
+// ApplyTrainingTargets copies targets onto ex.
+func ApplyTrainingTargets(ex *models.WorkoutPlanExercise, targets TrainingTargets) {
+	ex.RestSeconds = targets.RestSeconds
+	ex.Tempo = targets.Tempo
+	ex.TargetRPE = targets.TargetRPE
+	ex.TargetRIR = targets.TargetRIR
+}
+
+// TrainingTargetsOf returns the targets stored on ex.
+func TrainingTargetsOf(ex models.WorkoutPlanExercise) TrainingTargets {
+	return TrainingTargets{
+		RestSeconds: ex.RestSeconds,
+		Tempo:       ex.Tempo,
+		TargetRPE:   ex.TargetRPE,
+		TargetRIR:   ex.TargetRIR,
+	}
+}
+
+// RIRForRPE converts an RPE target to reps in reserve (RPE 8 = 2 reps left), rounded down.
+func RIRForRPE(rpe float64) int {
+	rir := int(math.Floor(MaxTargetRPE - rpe))
+	if rir < 0 {
+		return 0
+	}
+	if rir > MaxTargetRIR {
+		return MaxTargetRIR
+	}
+	return rir
+}
+
+// RPEForRIR converts reps in reserve to an RPE target.
+func RPEForRIR(rir int) float64 {
+	return math.Max(MinTargetRPE, MaxTargetRPE-float64(rir))
+}
+
+// ParseTempo splits a tempo such as "3-1-1-0" into its four phases in seconds. "X" marks an
+// explosive phase and is counted as one second.
+func ParseTempo(tempo string) ([]int, error) {
+	parts := strings.Split(strings.TrimSpace(tempo), "-")
+	if len(parts) != 4 {
+		return nil, fmt.Errorf("tempo must have four phases, e.g. 3-0-1-0")
+	}
+
+	phases := make([]int, len(parts))
+	for i, part := range parts {
+		if strings.EqualFold(part, "x") {
+			phases[i] = explosiveTempoSeconds
+			continue
+		}
+		seconds, err := strconv.Atoi(part)
+		if err != nil || seconds < 0 || seconds > 9 {
+			return nil, fmt.Errorf("tempo phases must be 0-9 seconds or X, got %q", part)
+		}
+		phases[i] = seconds
+	}
+	return phases, nil
+}
+
+// NormalizeTempo validates tempo and returns it in the stored form (upper-case X, no spaces).
+func NormalizeTempo(tempo string) (string, error) {
+	if _, err := ParseTempo(tempo); err != nil {
+		return "", err
+	}
+	return strings.ToUpper(strings.TrimSpace(tempo)), nil
+}
+
+// TempoSecondsPerRep is how long one rep takes at tempo. Missing or invalid tempos count as 4 seconds.
+func TempoSecondsPerRep(tempo string) int {
+	phases, err := ParseTempo(tempo)
+	if err != nil {
+		return 4
+	}
+
+	total := 0
+	for _, seconds := range phases {
+		total += seconds
+	}
+	if total == 0 {
+		return explosiveTempoSeconds
+	}
+	return total
+}
+
+// EstimateExerciseSeconds is the time an exercise takes: every rep at its tempo, rest between
+// sets, and the transition to the next exercise.
+func EstimateExerciseSeconds(sets, reps int, targets TrainingTargets) int {
+	if sets <= 0 {
+		return 0
+	}
+	work := sets * reps * TempoSecondsPerRep(targets.Tempo)
+	rest := (sets - 1) * targets.RestSeconds
+	return work + rest + exerciseTransitionSeconds
+}
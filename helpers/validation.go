@@ -2,7 +2,6 @@ package helpers
 
 import "strings"
 
-var allowedSplits = []string{"Push/Pull/Legs", "Upper/Lower", "Full Body", "Bro Split"}
 var allowedGoals = []string{"Muscle Gain", "Fat Loss", "Stamina", "General Fitness"}
 var allowedIntensities = []string{"Beginner", "Intermediate", "Advanced"}
 var allowedBMICategories = []string{"Underweight", "Normal", "Overweight", "Obese"}
@@ -19,9 +18,10 @@ var allowedEquipment = []string{
 	"Resistance Bands",
 }
 
-// Split
+// Split (built-in only; custom splits are resolved from the database)
 func IsValidSplitType(value string) bool {
-	return containsCaseInsensitive(allowedSplits, value)
+	_, ok := BuiltInSplit(value)
+	return ok
 }
 
 // Goal
@@ -0,0 +1,187 @@
+package helpers
+
+import (
+	"sort"
+	"strings"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+// MinSetsPerExercise is the floor BalanceWeeklySets never trims below.
+const MinSetsPerExercise = 2
+
+const (
+	VolumeStatusUnder  = "under"
+	VolumeStatusWithin = "within"
+	VolumeStatusOver   = "over"
+)
+
+// VolumeTarget is the weekly number of working sets each trained body part should receive.
+type VolumeTarget struct {
+	Min int
+	Max int
+}
+
+// WeeklyVolumeTarget returns the weekly set range per body part for a goal and intensity.
+// Hypertrophy work gets the most volume; every goal scales up with training experience.
+func WeeklyVolumeTarget(goal, intensity string) VolumeTarget {
+	level := difficultyRank[strings.ToLower(intensity)]
+	if level == 0 {
+		level = 1
+	}
+
+	switch strings.ToLower(goal) {
+	case "muscle gain":
+		return []VolumeTarget{{8, 12}, {10, 16}, {12, 20}}[level-1]
+	case "fat loss", "stamina":
+		return []VolumeTarget{{6, 10}, {8, 12}, {10, 14}}[level-1]
+	default:
+		return []VolumeTarget{{6, 10}, {8, 12}, {10, 16}}[level-1]
+	}
+}
+
+// VolumePlanner keeps a running total of weekly sets per body part while a plan is generated,
+// so later days favour muscles the earlier days left short.
+type VolumePlanner struct {
+	Target VolumeTarget
+	sets   map[string]int
+}
+
+func NewVolumePlanner(goal, intensity string) *VolumePlanner {
+	return &VolumePlanner{
+		Target: WeeklyVolumeTarget(goal, intensity),
+		sets:   map[string]int{},
+	}
+}
+
+func (v *VolumePlanner) Add(bodyPart string, sets int) {
+	v.sets[strings.ToLower(bodyPart)] += sets
+}
+
+// need scores how much bodyPart still needs volume once pendingSets are added:
+// 1 below the target range, 0.5 inside it and 0 at or above its maximum.
+func (v *VolumePlanner) need(bodyPart string, pendingSets int) float64 {
+	if v == nil {
+		return 0
+	}
+	total := v.sets[strings.ToLower(bodyPart)] + pendingSets
+	switch {
+	case total < v.Target.Min:
+		return 1
+	case total < v.Target.Max:
+		return 0.5
+	default:
+		return 0
+	}
+}
+
+type exerciseRef struct {
+	day int
+	ex  int
+}
+
+// BalanceWeeklySets adjusts the set counts of a generated week so each trained body part lands
+// inside target where the exercises allow it: short body parts get extra sets (up to
+// MaxSetsPerExercise per exercise) and overloaded ones are trimmed (down to MinSetsPerExercise).
+func BalanceWeeklySets(days []models.WorkoutPlanDay, exMap map[uint64]*models.Exercise, target VolumeTarget) {
+	refsByPart := map[string][]exerciseRef{}
+	totals := map[string]int{}
+
+	for d := range days {
+		for e, ex := range days[d].Exercises {
+			detail, ok := exMap[ex.ExerciseID]
+			if !ok || detail == nil || !IsRepsBased(ex) {
+				continue
+			}
+			part := strings.ToLower(detail.BodyPart)
+			refsByPart[part] = append(refsByPart[part], exerciseRef{day: d, ex: e})
+			totals[part] += ex.Sets
+		}
+	}
+
+	parts := make([]string, 0, len(refsByPart))
+	for part := range refsByPart {
+		parts = append(parts, part)
+	}
+	sort.Strings(parts)
+
+	for _, part := range parts {
+		refs := refsByPart[part]
+
+		for totals[part] < target.Min {
+			changed := false
+			for _, ref := range refs {
+				ex := &days[ref.day].Exercises[ref.ex]
+				if totals[part] >= target.Min {
+					break
+				}
+				if ex.Sets < MaxSetsPerExercise {
+					ex.Sets++
+					totals[part]++
+					changed = true
+				}
+			}
+			if !changed {
+				break
+			}
+		}
+
+		for totals[part] > target.Max {
+			changed := false
+			for i := len(refs) - 1; i >= 0; i-- {
+				ex := &days[refs[i].day].Exercises[refs[i].ex]
+				if totals[part] <= target.Max {
+					break
+				}
+				if ex.Sets > MinSetsPerExercise {
+					ex.Sets--
+					totals[part]--
+					changed = true
+				}
+			}
+			if !changed {
+				break
+			}
+		}
+	}
+}
+
+// SummarizeWeeklyVolume totals the prescribed weekly sets per body part and compares them with target.
+func SummarizeWeeklyVolume(days []models.WorkoutPlanDay, exMap map[uint64]*models.Exercise, target VolumeTarget) []dto.MuscleVolume {
+	totals := map[string]*dto.MuscleVolume{}
+
+	for _, day := range days {
+		for _, ex := range day.Exercises {
+			detail, ok := exMap[ex.ExerciseID]
+			if !ok || detail == nil || !IsRepsBased(ex) {
+				continue
+			}
+			key := strings.ToLower(detail.BodyPart)
+			if _, ok := totals[key]; !ok {
+				totals[key] = &dto.MuscleVolume{
+					BodyPart:  detail.BodyPart,
+					TargetMin: target.Min,
+					TargetMax: target.Max,
+				}
+			}
+			totals[key].Sets += ex.Sets
+			totals[key].Exercises++
+		}
+	}
+
+	result := make([]dto.MuscleVolume, 0, len(totals))
+	for _, volume := range totals {
+		switch {
+		case volume.Sets < target.Min:
+			volume.Status = VolumeStatusUnder
+		case volume.Sets > target.Max:
+			volume.Status = VolumeStatusOver
+		default:
+			volume.Status = VolumeStatusWithin
+		}
+		result = append(result, *volume)
+	}
+
+	sort.Slice(result, func(i, j int) bool { return result[i].BodyPart < result[j].BodyPart })
+	return result
+}
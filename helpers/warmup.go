@@ -0,0 +1,181 @@
+package helpers
+
+import (
+	"math"
+	"sort"
+	"strings"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+)
+
+// Blocks of a day's output, and the kinds of items warm-up, cool-down and recovery blocks hold.
+const (
+	BlockWarmup   = "warmup"
+	BlockMain     = "main"
+	BlockCooldown = "cooldown"
+	BlockRecovery = "recovery"
+
+	BlockItemCardio   = "cardio"
+	BlockItemMobility = "mobility"
+	BlockItemStretch  = "stretch"
+	BlockItemRampSet  = "ramp_set"
+
+	warmupCardioSeconds   = 3 * 60
+	warmupMobilitySeconds = 30
+	maxWarmupMobility     = 4
+	rampSetRestSeconds    = 45
+)
+
+// rampStep is one ramp-up set: a share of the working load for a number of reps.
+type rampStep struct {
+	Percent int
+	Reps    int
+}
+
+// rampSchemes lists ramp-up sets by working load: heavier lifts get more, smaller jumps.
+var rampSchemes = []struct {
+	MaxLoad float64
+	Steps   []rampStep
+}{
+	{MaxLoad: 20, Steps: []rampStep{{50, 8}}},
+	{MaxLoad: 60, Steps: []rampStep{{40, 8}, {70, 4}}},
+	{MaxLoad: math.Inf(1), Steps: []rampStep{{40, 8}, {60, 5}, {80, 3}}},
+}
+
+// mobilityDrill is a general warm-up drill and the body parts it prepares.
+type mobilityDrill struct {
+	Name      string
+	Reps      int
+	BodyParts []string
+}
+
+var mobilityDrills = []mobilityDrill{
+	{Name: "Leg swings", Reps: 10, BodyParts: []string{"quadriceps", "hamstrings", "glutes", "abductors", "adductors"}},
+	{Name: "Bodyweight squats", Reps: 10, BodyParts: []string{"quadriceps", "glutes", "calves"}},
+	{Name: "Hip circles", Reps: 10, BodyParts: []string{"glutes", "hamstrings", "lower back", "abductors", "adductors"}},
+	{Name: "Arm circles", Reps: 10, BodyParts: []string{"shoulders", "chest", "triceps", "traps"}},
+	{Name: "Scapular push-ups", Reps: 10, BodyParts: []string{"chest", "shoulders", "triceps"}},
+	{Name: "Thoracic rotations", Reps: 8, BodyParts: []string{"lats", "middle back", "traps", "biceps", "forearms", "neck"}},
+	{Name: "Cat-cow", Reps: 10, BodyParts: []string{"lower back", "abdominals", "middle back"}},
+	{Name: "Dead bugs", Reps: 8, BodyParts: []string{"abdominals", "lower back"}},
+}
+
+// BuildWarmup lays out the warm-up for a training day: a few minutes of light cardio, mobility
+// drills for the day's body parts (leaving out any the user's limitations avoid), and ramp-up sets
+// for the first compound lift scaled to its working load. Pass exercises already adjusted for
+// phase so the ramp-up follows the week's load.
+func BuildWarmup(bodyParts []string, exercises []models.WorkoutPlanExercise, exMap map[uint64]*models.Exercise, limitations []dto.Limitation) []dto.BlockItem {
+	if len(exercises) == 0 {
+		return nil
+	}
+
+	items := []dto.BlockItem{{
+		Block:           BlockWarmup,
+		Type:            BlockItemCardio,
+		Name:            "Light cardio (brisk walk, easy bike or jumping jacks)",
+		DurationSeconds: warmupCardioSeconds,
+	}}
+
+	for _, drill := range mobilityDrillsFor(bodyParts, limitations) {
+		items = append(items, dto.BlockItem{
+			Block:           BlockWarmup,
+			Type:            BlockItemMobility,
+			Name:            drill.Name,
+			Reps:            drill.Reps,
+			DurationSeconds: warmupMobilitySeconds,
+		})
+	}
+
+	return append(items, rampUpSets(exercises, exMap)...)
+}
+
+// EstimateBlockSeconds sums the duration of warm-up, cool-down or recovery items.
+func EstimateBlockSeconds(items []dto.BlockItem) int {
+	total := 0
+	for _, item := range items {
+		total += item.DurationSeconds
+	}
+	return total
+}
+
+// mobilityDrillsFor picks the drills covering the most of bodyParts, in catalogue order for ties.
+// Drills an "avoid" limitation matches are skipped.
+func mobilityDrillsFor(bodyParts []string, limitations []dto.Limitation) []mobilityDrill {
+	wanted := map[string]bool{}
+	for _, part := range bodyParts {
+		wanted[strings.ToLower(part)] = true
+	}
+
+	type scored struct {
+		drill mobilityDrill
+		hits  int
+	}
+	var candidates []scored
+	for _, drill := range mobilityDrills {
+		if avoidsItem(drill.Name, drill.BodyParts, limitations) {
+			continue
+		}
+		hits := 0
+		for _, part := range drill.BodyParts {
+			if wanted[part] {
+				hits++
+			}
+		}
+		if hits > 0 {
+			candidates = append(candidates, scored{drill: drill, hits: hits})
+		}
+	}
+	sort.SliceStable(candidates, func(i, j int) bool { return candidates[i].hits > candidates[j].hits })
+
+	var drills []mobilityDrill
+	for i := 0; i < len(candidates) && i < maxWarmupMobility; i++ {
+		drills = append(drills, candidates[i].drill)
+	}
+	return drills
+}
+
+// rampUpSets builds the ramp-up sets for the first compound lift of the day, if there is one.
+// Without a prescribed load there is nothing to scale, so a single light set is suggested.
+func rampUpSets(exercises []models.WorkoutPlanExercise, exMap map[uint64]*models.Exercise) []dto.BlockItem {
+	sorted := append([]models.WorkoutPlanExercise(nil), exercises...)
+	sort.SliceStable(sorted, func(i, j int) bool { return sorted[i].Order < sorted[j].Order })
+
+	for _, ex := range sorted {
+		detail, ok := exMap[ex.ExerciseID]
+		if !ok || detail == nil || !strings.EqualFold(detail.ExerciseType, "Compound") {
+			continue
+		}
+
+		steps := []rampStep{{Percent: 50, Reps: 8}}
+		if ex.Load > 0 {
+			for _, scheme := range rampSchemes {
+				if ex.Load <= scheme.MaxLoad {
+					steps = scheme.Steps
+					break
+				}
+			}
+		}
+
+		items := make([]dto.BlockItem, 0, len(steps))
+		for _, step := range steps {
+			reps := step.Reps
+			if ex.Reps > 0 && reps > ex.Reps {
+				reps = ex.Reps
+			}
+			items = append(items, dto.BlockItem{
+				Block:                BlockWarmup,
+				Type:                 BlockItemRampSet,
+				Name:                 detail.Name,
+				PlanExerciseID:       ex.ID,
+				ExerciseID:           ex.ExerciseID,
+				Sets:                 1,
+				Reps:                 reps,
+				Load:                 math.Round(ex.Load*float64(step.Percent)/100*2) / 2,
+				PercentOfWorkingLoad: step.Percent,
+				DurationSeconds:      reps*TempoSecondsPerRep(ex.Tempo) + rampSetRestSeconds,
+			})
+		}
+		return items
+	}
+	return nil
+}
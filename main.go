@@ -3,15 +3,21 @@ package main
 import (
 	"log"
 	"wellnesspath/config"
+	"wellnesspath/helpers"
 
 	"wellnesspath/routes"
 	seeder "wellnesspath/seeders"
+	"wellnesspath/services"
 )
 
 func main() {
 	// Load configuration
 	config.LoadConfig()
 
+	if err := helpers.LoadSelectionWeights(config.ENV.SelectionWeightsFile); err != nil {
+		log.Fatal("Failed to load selection weights:", err)
+	}
+
 	// Connect to the database
 	if config.ENV.Environment == "Hosted" {
 		config.ConnectDatabase()
@@ -28,6 +34,13 @@ func main() {
 		}
 	}
 
+	if err := seeder.SeedFocusMappingsFromFile(); err != nil {
+		log.Fatal("Seeding focus mappings failed:", err)
+	}
+	if err := services.LoadFocusRegistry(); err != nil {
+		log.Fatal("Failed to load focus registry:", err)
+	}
+
 	// Initialize router
 	router := routes.SetupRouter()
 
@@ -0,0 +1,30 @@
+package middleware
+
+import (
+	"net/http"
+	"wellnesspath/config"
+	"wellnesspath/repositories"
+
+	"github.com/gin-gonic/gin"
+)
+
+// RequireAdmin must run after AuthenticateJWT; it rejects users without the admin flag.
+func RequireAdmin() gin.HandlerFunc {
+	return func(c *gin.Context) {
+		userID, exists := c.Get("userID")
+		if !exists {
+			c.JSON(http.StatusUnauthorized, gin.H{"error": "User not authenticated"})
+			c.Abort()
+			return
+		}
+
+		user, err := repositories.GetUserByID(config.DB, userID.(uint64))
+		if err != nil || user.IsDeleted || !user.IsAdmin {
+			c.JSON(http.StatusForbidden, gin.H{"error": "Admin access required"})
+			c.Abort()
+			return
+		}
+
+		c.Next()
+	}
+}
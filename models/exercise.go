@@ -13,6 +13,7 @@ type Exercise struct {
 	Description            string    `gorm:"type:text"`
 	StepByStepInstructions string    `gorm:"type:text"`
 	Equipment              string    `gorm:"type:varchar(255)"`
+	Rating                 float64   `gorm:"not null;default:0"`
 	IsDeleted              bool      `gorm:"default:false"`
 	CreatedAt              time.Time `gorm:"autoCreateTime"`
 	UpdatedAt              time.Time `gorm:"autoUpdateTime"`
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+const (
+	ExercisePreferenceFavourite = "favourite"
+	ExercisePreferenceBlocked   = "blocked"
+)
+
+// UserExercisePreference marks an exercise as a favourite (preferred by generation) or
+// blocked (never picked) for one user.
+type UserExercisePreference struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement"`
+	UserID     uint64    `gorm:"not null;uniqueIndex:idx_user_exercise_preference"`
+	ExerciseID uint64    `gorm:"not null;uniqueIndex:idx_user_exercise_preference"`
+	Preference string    `gorm:"type:varchar(20);not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
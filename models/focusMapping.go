@@ -0,0 +1,12 @@
+package models
+
+import "time"
+
+// FocusMapping maps a split focus (e.g. "Push") to the exercise body parts it trains.
+type FocusMapping struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement"`
+	Focus         string    `gorm:"type:varchar(50);not null;uniqueIndex"`
+	BodyPartsJSON string    `gorm:"type:text"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
@@ -0,0 +1,19 @@
+package models
+
+import "time"
+
+// PersonalRecord is a logged set that beat the user's previous best estimated one-rep max for its exercise.
+type PersonalRecord struct {
+	ID             uint64    `gorm:"primaryKey;autoIncrement"`
+	UserID         uint64    `gorm:"not null;index"`
+	ExerciseID     uint64    `gorm:"not null;index"`
+	SessionSetID   uint64    `gorm:"not null"`
+	Reps           int       `gorm:"not null"`
+	Load           float64   `gorm:"not null"`
+	EstimatedOneRM float64   `gorm:"not null"`
+	PreviousOneRM  float64   `gorm:"not null;default:0"`
+	Formula        string    `gorm:"type:varchar(10);not null"`
+	AchievedAt     time.Time `gorm:"not null"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
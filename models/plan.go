@@ -2,13 +2,26 @@ package models
 
 import "time"
 
+// WorkoutPlan is one version of a user's plan. Generating, shuffling or applying a draft always
+// creates a new version. Edits (replacing an exercise, editing reps or targets, switching a rest
+// day's type and applying progression) rewrite the active version only while no session has been
+// logged against it; otherwise the version is copied into a new active version with the same
+// start date and seed, BasePlanID pointing at the original, and the edit goes to the copy.
+// A version that has been trained therefore always shows the prescription that was trained.
 type WorkoutPlan struct {
-	ID        uint64           `gorm:"primaryKey;autoIncrement"`
-	UserID    uint64           `gorm:"not null"`
-	SplitType string           `gorm:"type:varchar(50);not null"`
-	Goal      string           `gorm:"type:varchar(100);not null"`
-	IsDeleted bool             `gorm:"default:false"`
-	CreatedAt time.Time        `gorm:"autoCreateTime"`
-	UpdatedAt time.Time        `gorm:"autoUpdateTime"`
-	Days      []WorkoutPlanDay `gorm:"foreignKey:PlanID"`
+	ID         uint64            `gorm:"primaryKey;autoIncrement"`
+	UserID     uint64            `gorm:"not null"`
+	SplitType  string            `gorm:"type:varchar(50);not null"`
+	Goal       string            `gorm:"type:varchar(100);not null"`
+	StartDate  time.Time         `gorm:"type:date"`
+	Version    int               `gorm:"not null;default:1"`
+	IsActive   bool              `gorm:"not null;default:false"`
+	IsDraft    bool              `gorm:"not null;default:false"`
+	BasePlanID uint64            `gorm:"default:0"`
+	Seed       int64             `gorm:"not null;default:0"`
+	IsDeleted  bool              `gorm:"default:false"`
+	CreatedAt  time.Time         `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time         `gorm:"autoUpdateTime"`
+	Days       []WorkoutPlanDay  `gorm:"foreignKey:PlanID"`
+	Weeks      []WorkoutPlanWeek `gorm:"foreignKey:PlanID"`
 }
@@ -2,12 +2,28 @@ package models
 
 import "time"
 
+// Day types: rest days have no exercises, and active-recovery days are rest days the user
+// has chosen to fill with the suggested recovery work.
+const (
+	DayTypeTraining       = "training"
+	DayTypeRest           = "rest"
+	DayTypeActiveRecovery = "active_recovery"
+)
+
+// IsRestDay reports whether day has no training, whether or not the user does active recovery on it.
+func IsRestDay(day WorkoutPlanDay) bool {
+	return day.DayType == DayTypeRest || day.DayType == DayTypeActiveRecovery
+}
+
 type WorkoutPlanDay struct {
-	ID        uint64                `gorm:"primaryKey;autoIncrement"`
-	PlanID    uint64                `gorm:"not null"`
-	DayNumber int                   `gorm:"not null"`
-	Focus     string                `gorm:"type:varchar(50);not null"`
-	CreatedAt time.Time             `gorm:"autoCreateTime"`
-	UpdatedAt time.Time             `gorm:"autoUpdateTime"`
-	Exercises []WorkoutPlanExercise `gorm:"foreignKey:DayID"`
+	ID            uint64                `gorm:"primaryKey;autoIncrement"`
+	PlanID        uint64                `gorm:"not null"`
+	DayNumber     int                   `gorm:"not null"`
+	DayType       string                `gorm:"type:varchar(20);not null;default:'training'"`
+	Focus         string                `gorm:"type:varchar(50);not null"`
+	BodyPartsJSON string                `gorm:"type:text"`
+	CreatedAt     time.Time             `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time             `gorm:"autoUpdateTime"`
+	Exercises     []WorkoutPlanExercise `gorm:"foreignKey:DayID"`
+	Groups        []WorkoutPlanGroup    `gorm:"foreignKey:DayID"`
 }
@@ -2,14 +2,43 @@ package models
 
 import "time"
 
+// Modes of a plan exercise: sets of reps, one continuous bout of DurationSeconds, or Sets
+// intervals of WorkSeconds with RestSeconds of rest in between.
+const (
+	ExerciseModeReps     = "reps"
+	ExerciseModeDuration = "duration"
+	ExerciseModeInterval = "interval"
+)
+
 type WorkoutPlanExercise struct {
-	ID         uint64    `gorm:"primaryKey;autoIncrement"`
-	DayID      uint64    `gorm:"not null"`
-	ExerciseID uint64    `gorm:"not null"`
-	Order      int       `gorm:"not null"`
-	Reps       int       `gorm:"not null"`
-	Sets       int       `gorm:"not null"`
-	Note       string    `gorm:"type:text"`
-	CreatedAt  time.Time `gorm:"autoCreateTime"`
-	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+	ID                  uint64    `gorm:"primaryKey;autoIncrement"`
+	DayID               uint64    `gorm:"not null"`
+	ExerciseID          uint64    `gorm:"not null"`
+	Order               int       `gorm:"not null"`
+	GroupNumber         int       `gorm:"not null;default:0"`
+	Mode                string    `gorm:"type:varchar(10);not null;default:'reps'"`
+	Reps                int       `gorm:"not null"`
+	Sets                int       `gorm:"not null"`
+	Load                float64   `gorm:"not null;default:0"`
+	RestSeconds         int       `gorm:"not null;default:60"`
+	Tempo               string    `gorm:"type:varchar(15);not null;default:'2-0-2-0'"`
+	TargetRPE           float64   `gorm:"not null;default:7"`
+	TargetRIR           int       `gorm:"not null;default:3"`
+	DurationSeconds     int       `gorm:"not null;default:0"`
+	DistanceMeters      int       `gorm:"not null;default:0"`
+	TargetPace          string    `gorm:"type:varchar(10)"`
+	HeartRateZone       int       `gorm:"not null;default:0"`
+	WorkSeconds         int       `gorm:"not null;default:0"`
+	Note                string    `gorm:"type:text"`
+	SelectionReasonJSON string    `gorm:"type:text"`
+	CreatedAt           time.Time `gorm:"autoCreateTime"`
+	UpdatedAt           time.Time `gorm:"autoUpdateTime"`
+	// ProgressedAt is when reps, sets or load last changed, through an accepted progression
+	// proposal or a manual edit. ProposalDismissedAt is when a proposal was last rejected.
+	// Progression only reads sets logged after both.
+	ProgressedAt        *time.Time
+	ProposalDismissedAt *time.Time
+	// OriginPlanExerciseID is the plan exercise this one was first copied from when an edit
+	// copied its plan into a new version (see WorkoutPlan), or 0 if it is the original.
+	OriginPlanExerciseID uint64 `gorm:"not null;default:0;index"`
 }
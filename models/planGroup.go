@@ -0,0 +1,22 @@
+package models
+
+import "time"
+
+const (
+	GroupTypeSuperset = "superset"
+	GroupTypeCircuit  = "circuit"
+	GroupTypeGiantSet = "giant_set"
+)
+
+// WorkoutPlanGroup makes exercises of a day that share its GroupNumber run back to back,
+// repeated for Rounds rounds with RestBetweenRoundsSeconds of rest after each round.
+type WorkoutPlanGroup struct {
+	ID                       uint64    `gorm:"primaryKey;autoIncrement"`
+	DayID                    uint64    `gorm:"not null;index"`
+	GroupNumber              int       `gorm:"not null"`
+	Type                     string    `gorm:"type:varchar(20);not null"`
+	Rounds                   int       `gorm:"not null"`
+	RestBetweenRoundsSeconds int       `gorm:"not null;default:0"`
+	CreatedAt                time.Time `gorm:"autoCreateTime"`
+	UpdatedAt                time.Time `gorm:"autoUpdateTime"`
+}
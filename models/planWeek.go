@@ -0,0 +1,14 @@
+package models
+
+import "time"
+
+// WorkoutPlanWeek is one week of a plan's mesocycle. Its phase adjusts the base prescription
+// stored on WorkoutPlanExercise for every session that falls in the week.
+type WorkoutPlanWeek struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement"`
+	PlanID     uint64    `gorm:"not null;index"`
+	WeekNumber int       `gorm:"not null"`
+	Phase      string    `gorm:"type:varchar(20);not null"`
+	CreatedAt  time.Time `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time `gorm:"autoUpdateTime"`
+}
@@ -8,6 +8,7 @@ type Profile struct {
 	SplitType          string `gorm:"type:varchar(50);not null"`
 	Intensity          string `gorm:"type:varchar(50);not null"`
 	TargetWeight       float64
+	BodyWeight         float64
 	BMI                float64
 	BMICategory        string `gorm:"type:varchar(50)"`
 	Frequency          int
@@ -15,6 +16,8 @@ type Profile struct {
 	Goal               string    `gorm:"type:varchar(100)"`
 	EquipmentJSON      string    `gorm:"type:text"`
 	RestDaysJSON       string    `gorm:"type:text"`
+	LimitationsJSON    string    `gorm:"type:text"`
+	Timezone           string    `gorm:"type:varchar(64);default:'UTC'"`
 	IsDeleted          bool      `gorm:"default:false"`
 	CreatedAt          time.Time `gorm:"autoCreateTime"`
 	UpdatedAt          time.Time `gorm:"autoUpdateTime"`
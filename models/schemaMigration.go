@@ -0,0 +1,8 @@
+package models
+
+import "time"
+
+type SchemaMigration struct {
+	ID        string    `gorm:"primaryKey;type:varchar(100)"`
+	AppliedAt time.Time `gorm:"autoCreateTime"`
+}
@@ -0,0 +1,23 @@
+package models
+
+import "time"
+
+const (
+	SessionStatusInProgress = "In Progress"
+	SessionStatusCompleted  = "Completed"
+)
+
+type WorkoutSession struct {
+	ID         uint64    `gorm:"primaryKey;autoIncrement"`
+	UserID     uint64    `gorm:"not null;index"`
+	PlanID     uint64    `gorm:"not null"`
+	DayID      uint64    `gorm:"not null"`
+	Status     string    `gorm:"type:varchar(50);not null"`
+	StartedAt  time.Time `gorm:"not null"`
+	FinishedAt *time.Time
+	Note       string              `gorm:"type:text"`
+	IsDeleted  bool                `gorm:"default:false"`
+	CreatedAt  time.Time           `gorm:"autoCreateTime"`
+	UpdatedAt  time.Time           `gorm:"autoUpdateTime"`
+	Sets       []WorkoutSessionSet `gorm:"foreignKey:SessionID"`
+}
@@ -0,0 +1,17 @@
+package models
+
+import "time"
+
+type WorkoutSessionSet struct {
+	ID             uint64    `gorm:"primaryKey;autoIncrement"`
+	SessionID      uint64    `gorm:"not null;index"`
+	PlanExerciseID uint64    `gorm:"not null"`
+	ExerciseID     uint64    `gorm:"not null"`
+	SetNumber      int       `gorm:"not null"`
+	Reps           int       `gorm:"not null"`
+	Load           float64   `gorm:"not null;default:0"`
+	RPE            float64   `gorm:"default:0"`
+	CompletedAt    time.Time `gorm:"not null"`
+	CreatedAt      time.Time `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time `gorm:"autoUpdateTime"`
+}
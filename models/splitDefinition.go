@@ -0,0 +1,18 @@
+package models
+
+import "time"
+
+// SplitDefinition is a user-defined split; splits without an owner are global templates managed by admins.
+type SplitDefinition struct {
+	ID             uint64       `gorm:"primaryKey;autoIncrement"`
+	OwnerID        *uint64      `gorm:"index"`
+	Name           string       `gorm:"type:varchar(50);not null"`
+	Description    string       `gorm:"type:text"`
+	MinFrequency   int          `gorm:"not null;default:1"`
+	MaxFrequency   int          `gorm:"not null;default:7"`
+	FullCyclesOnly bool         `gorm:"not null;default:false"`
+	IsDeleted      bool         `gorm:"default:false"`
+	CreatedAt      time.Time    `gorm:"autoCreateTime"`
+	UpdatedAt      time.Time    `gorm:"autoUpdateTime"`
+	Focuses        []SplitFocus `gorm:"foreignKey:SplitID"`
+}
@@ -0,0 +1,13 @@
+package models
+
+import "time"
+
+type SplitFocus struct {
+	ID            uint64    `gorm:"primaryKey;autoIncrement"`
+	SplitID       uint64    `gorm:"not null;index"`
+	Order         int       `gorm:"not null"`
+	Name          string    `gorm:"type:varchar(50);not null"`
+	BodyPartsJSON string    `gorm:"type:text"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
+}
@@ -3,12 +3,14 @@ package models
 import "time"
 
 type User struct {
-	ID        uint64    `gorm:"primaryKey;autoIncrement"`
-	Name      string    `gorm:"type:varchar(255);not null"`
-	Profile   string    `gorm:"type:varchar(255)"`
-	Username  string    `gorm:"type:varchar(255);unique;not null"`
-	Password  string    `gorm:"type:varchar(255);not null"`
-	IsDeleted bool      `gorm:"default:false"`
-	CreatedAt time.Time `gorm:"autoCreateTime"`
-	UpdatedAt time.Time `gorm:"autoUpdateTime"`
+	ID            uint64    `gorm:"primaryKey;autoIncrement"`
+	Name          string    `gorm:"type:varchar(255);not null"`
+	Profile       string    `gorm:"type:varchar(255)"`
+	Username      string    `gorm:"type:varchar(255);unique;not null"`
+	Password      string    `gorm:"type:varchar(255);not null"`
+	CalendarToken string    `gorm:"type:varchar(64);index"`
+	IsAdmin       bool      `gorm:"not null;default:false"`
+	IsDeleted     bool      `gorm:"default:false"`
+	CreatedAt     time.Time `gorm:"autoCreateTime"`
+	UpdatedAt     time.Time `gorm:"autoUpdateTime"`
 }
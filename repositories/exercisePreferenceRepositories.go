@@ -0,0 +1,43 @@
+package repositories
+
+import (
+	"wellnesspath/config"
+	"wellnesspath/models"
+
+	"gorm.io/gorm"
+)
+
+func GetExercisePreferencesByUserID(userID uint64) ([]models.UserExercisePreference, error) {
+	var preferences []models.UserExercisePreference
+	err := config.DB.
+		Where("user_id = ?", userID).
+		Order("updated_at DESC").
+		Find(&preferences).Error
+	return preferences, err
+}
+
+// SetExercisePreferenceTx stores preference for the exercise, replacing any earlier one.
+func SetExercisePreferenceTx(tx *gorm.DB, userID uint64, exerciseID uint64, preference string) error {
+	result := tx.
+		Model(&models.UserExercisePreference{}).
+		Where("user_id = ? AND exercise_id = ?", userID, exerciseID).
+		Update("preference", preference)
+	if result.Error != nil {
+		return result.Error
+	}
+	if result.RowsAffected > 0 {
+		return nil
+	}
+	return tx.Create(&models.UserExercisePreference{
+		UserID:     userID,
+		ExerciseID: exerciseID,
+		Preference: preference,
+	}).Error
+}
+
+func DeleteExercisePreference(userID uint64, exerciseID uint64) (bool, error) {
+	result := config.DB.
+		Where("user_id = ? AND exercise_id = ?", userID, exerciseID).
+		Delete(&models.UserExercisePreference{})
+	return result.RowsAffected > 0, result.Error
+}
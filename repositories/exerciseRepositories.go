@@ -1,10 +1,38 @@
 package repositories
 
 import (
+	"strings"
 	"wellnesspath/config"
 	"wellnesspath/models"
+
+	"gorm.io/gorm"
 )
 
+// ExerciseFilter leaves out exercises a user must avoid: whole body parts, exercises whose
+// name contains one of the keywords (used for movement patterns) and individual exercises.
+type ExerciseFilter struct {
+	ExcludedBodyParts    []string
+	ExcludedNameKeywords []string
+	ExcludedIDs          []uint64
+}
+
+func (f ExerciseFilter) apply(query *gorm.DB) *gorm.DB {
+	if len(f.ExcludedIDs) > 0 {
+		query = query.Where("id NOT IN ?", f.ExcludedIDs)
+	}
+	if len(f.ExcludedBodyParts) > 0 {
+		lowered := make([]string, 0, len(f.ExcludedBodyParts))
+		for _, part := range f.ExcludedBodyParts {
+			lowered = append(lowered, strings.ToLower(part))
+		}
+		query = query.Where("LOWER(body_part) NOT IN ?", lowered)
+	}
+	for _, keyword := range f.ExcludedNameKeywords {
+		query = query.Where("LOWER(name) NOT LIKE ?", "%"+strings.ToLower(keyword)+"%")
+	}
+	return query
+}
+
 func GetAllExercises() ([]models.Exercise, error) {
 	var exercises []models.Exercise
 	if err := config.DB.Where("is_deleted = ?", false).Find(&exercises).Error; err != nil {
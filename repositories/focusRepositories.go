@@ -0,0 +1,46 @@
+package repositories
+
+import (
+	"wellnesspath/config"
+	"wellnesspath/models"
+)
+
+func GetAllFocusMappings() ([]models.FocusMapping, error) {
+	var mappings []models.FocusMapping
+	err := config.DB.Order("focus").Find(&mappings).Error
+	return mappings, err
+}
+
+func GetFocusMappingByID(id uint64) (models.FocusMapping, error) {
+	var mapping models.FocusMapping
+	err := config.DB.Where("id = ?", id).First(&mapping).Error
+	return mapping, err
+}
+
+// FocusMappingExists checks for another mapping with the same focus name.
+func FocusMappingExists(focus string, excludeID uint64) (bool, error) {
+	var count int64
+	err := config.DB.
+		Model(&models.FocusMapping{}).
+		Where("LOWER(focus) = LOWER(?) AND id <> ?", focus, excludeID).
+		Count(&count).Error
+	return count > 0, err
+}
+
+func CreateFocusMapping(mapping *models.FocusMapping) error {
+	return config.DB.Create(mapping).Error
+}
+
+func UpdateFocusMapping(mapping *models.FocusMapping) error {
+	return config.DB.
+		Model(&models.FocusMapping{}).
+		Where("id = ?", mapping.ID).
+		Updates(map[string]interface{}{
+			"focus":           mapping.Focus,
+			"body_parts_json": mapping.BodyPartsJSON,
+		}).Error
+}
+
+func DeleteFocusMapping(id uint64) error {
+	return config.DB.Where("id = ?", id).Delete(&models.FocusMapping{}).Error
+}
@@ -3,6 +3,7 @@ package repositories
 import (
 	"fmt"
 	"strings"
+	"time"
 	"wellnesspath/config"
 	"wellnesspath/models"
 
@@ -13,12 +14,20 @@ func CreateWorkoutPlanTx(tx *gorm.DB, plan *models.WorkoutPlan) error {
 	return tx.Create(plan).Error
 }
 
+func CreateWorkoutPlanWeeksTx(tx *gorm.DB, weeks []models.WorkoutPlanWeek) error {
+	return tx.Create(&weeks).Error
+}
+
+func orderPlanWeeks(db *gorm.DB) *gorm.DB {
+	return db.Order("week_number")
+}
+
 func CreateWorkoutPlanDayTx(tx *gorm.DB, day *models.WorkoutPlanDay) error {
 	return tx.Create(day).Error
 }
 
-func CreateWorkoutPlanExerciseTx(tx *gorm.DB, ex *models.WorkoutPlanExercise) error {
-	return tx.Create(ex).Error
+func CreateWorkoutPlanGroupsTx(tx *gorm.DB, groups []models.WorkoutPlanGroup) error {
+	return tx.Create(&groups).Error
 }
 
 func CreateWorkoutPlanExercisesBatchTx(tx *gorm.DB, exercises []models.WorkoutPlanExercise) error {
@@ -28,7 +37,7 @@ func CreateWorkoutPlanExercisesBatchTx(tx *gorm.DB, exercises []models.WorkoutPl
 // Admin Function (Optional)
 func GetAllWorkoutPlansByUserID(userID uint64) ([]models.WorkoutPlan, error) {
 	var plans []models.WorkoutPlan
-	err := config.DB.Where("user_id = ? AND is_deleted = ?", userID, false).Find(&plans).Error
+	err := config.DB.Where("user_id = ? AND is_draft = ? AND is_deleted = ?", userID, false, false).Find(&plans).Error
 	return plans, err
 }
 
@@ -36,18 +45,111 @@ func GetWorkoutPlanWithDetails(userID uint64) (models.WorkoutPlan, error) {
 	var plan models.WorkoutPlan
 	err := config.DB.
 		Preload("Days.Exercises").
-		Where("user_id = ? AND is_deleted = ?", userID, false).
+		Preload("Days.Groups").
+		Preload("Weeks", orderPlanWeeks).
+		Where("user_id = ? AND is_active = ? AND is_deleted = ?", userID, true, false).
 		Order("id").
 		First(&plan).Error
 	return plan, err
 }
 
-func GetExercisesByGoalAndEquipment(goal string, equipmentList []string) ([]models.Exercise, error) {
+func GetWorkoutPlanVersionsByUserID(userID uint64) ([]models.WorkoutPlan, error) {
+	var plans []models.WorkoutPlan
+	err := config.DB.
+		Where("user_id = ? AND is_draft = ? AND is_deleted = ?", userID, false, false).
+		Order("version DESC").
+		Find(&plans).Error
+	return plans, err
+}
+
+func GetWorkoutPlanByIDForUser(userID uint64, planID uint64) (models.WorkoutPlan, error) {
+	var plan models.WorkoutPlan
+	err := config.DB.
+		Preload("Days.Exercises").
+		Preload("Days.Groups").
+		Preload("Weeks", orderPlanWeeks).
+		Where("id = ? AND user_id = ? AND is_draft = ? AND is_deleted = ?", planID, userID, false, false).
+		First(&plan).Error
+	return plan, err
+}
+
+func GetWorkoutPlanDraftByID(userID uint64, planID uint64) (models.WorkoutPlan, error) {
+	var plan models.WorkoutPlan
+	err := config.DB.
+		Where("id = ? AND user_id = ? AND is_draft = ? AND is_deleted = ?", planID, userID, true, false).
+		First(&plan).Error
+	return plan, err
+}
+
+func DeleteWorkoutPlanDraftsByUserIDTx(tx *gorm.DB, userID uint64) error {
+	return tx.
+		Model(&models.WorkoutPlan{}).
+		Where("user_id = ? AND is_draft = ? AND is_deleted = ?", userID, true, false).
+		Update("is_deleted", true).Error
+}
+
+func PublishWorkoutPlanDraftTx(tx *gorm.DB, plan *models.WorkoutPlan) error {
+	return tx.
+		Model(&models.WorkoutPlan{}).
+		Where("id = ? AND is_draft = ?", plan.ID, true).
+		Updates(map[string]interface{}{
+			"is_draft":   false,
+			"is_active":  plan.IsActive,
+			"version":    plan.Version,
+			"start_date": plan.StartDate,
+		}).Error
+}
+
+func GetWorkoutPlansByIDs(ids []uint64) (map[uint64]models.WorkoutPlan, error) {
+	var plans []models.WorkoutPlan
+	if err := config.DB.Where("id IN ?", ids).Find(&plans).Error; err != nil {
+		return nil, err
+	}
+
+	planMap := make(map[uint64]models.WorkoutPlan)
+	for _, plan := range plans {
+		planMap[plan.ID] = plan
+	}
+	return planMap, nil
+}
+
+// GetNextWorkoutPlanVersionTx counts deleted plans too, so version numbers are never reused.
+func GetNextWorkoutPlanVersionTx(tx *gorm.DB, userID uint64) (int, error) {
+	var latest *int
+	err := tx.Model(&models.WorkoutPlan{}).
+		Where("user_id = ? AND is_draft = ?", userID, false).
+		Select("MAX(version)").
+		Scan(&latest).Error
+	if err != nil {
+		return 0, err
+	}
+	if latest == nil {
+		return 1, nil
+	}
+	return *latest + 1, nil
+}
+
+func DeactivateWorkoutPlansByUserIDTx(tx *gorm.DB, userID uint64) error {
+	return tx.
+		Model(&models.WorkoutPlan{}).
+		Where("user_id = ? AND is_active = ?", userID, true).
+		Update("is_active", false).Error
+}
+
+func ActivateWorkoutPlanTx(tx *gorm.DB, planID uint64, startDate time.Time) error {
+	return tx.
+		Model(&models.WorkoutPlan{}).
+		Where("id = ?", planID).
+		Updates(map[string]interface{}{"is_active": true, "start_date": startDate}).Error
+}
+
+func GetExercisesByGoalAndEquipment(goal string, equipmentList []string, filter ExerciseFilter) ([]models.Exercise, error) {
 	var exercises []models.Exercise
 
 	query := config.DB.
 		Where("is_deleted = ?", false).
 		Where("LOWER(goal_tag) = ? OR LOWER(goal_tag) = ?", strings.ToLower(goal), "general fitness")
+	query = filter.apply(query)
 
 	if len(equipmentList) > 0 {
 		query = query.Where(buildEquipmentCondition(equipmentList))
@@ -57,46 +159,31 @@ func GetExercisesByGoalAndEquipment(goal string, equipmentList []string) ([]mode
 		CASE 
 			WHEN LOWER(equipment) = 'body only' THEN 1 
 			ELSE 0 
-		END
+		END, id
 	`)
 
 	err := query.Find(&exercises).Error
 	return exercises, err
 }
 
-func DeleteWorkoutPlanByUserID(tx *gorm.DB, userID uint64) error {
+// DeleteActiveWorkoutPlanByUserID soft-deletes the active version only; earlier versions stay in the history.
+func DeleteActiveWorkoutPlanByUserID(tx *gorm.DB, userID uint64) error {
 	return tx.
 		Model(&models.WorkoutPlan{}).
-		Where("user_id = ? AND is_deleted = ?", userID, false).
-		Update("is_deleted", true).Error
-}
-
-func DeleteFullWorkoutPlanByUserIDTx(tx *gorm.DB, userID uint64) error {
-	var plans []models.WorkoutPlan
-	if err := tx.Where("user_id = ? AND is_deleted = 0", userID).Find(&plans).Error; err != nil {
-		return err
-	}
-	for _, plan := range plans {
-		if err := tx.Where("day_id IN (?)",
-			tx.Table("workout_plan_days").Select("id").Where("plan_id = ?", plan.ID),
-		).Delete(&models.WorkoutPlanExercise{}).Error; err != nil {
-			return err
-		}
-		if err := tx.Where("plan_id = ?", plan.ID).Delete(&models.WorkoutPlanDay{}).Error; err != nil {
-			return err
-		}
-		if err := tx.Model(&models.WorkoutPlan{}).Where("id = ?", plan.ID).Update("is_deleted", 1).Error; err != nil {
-			return err
-		}
-	}
-	return nil
+		Where("user_id = ? AND is_active = ? AND is_deleted = ?", userID, true, false).
+		Updates(map[string]interface{}{
+			"is_deleted": true,
+			"is_active":  false,
+		}).Error
 }
 
 func GetActiveWorkoutPlanByUserID(userID uint64) (models.WorkoutPlan, error) {
 	var plan models.WorkoutPlan
 	err := config.DB.
 		Preload("Days.Exercises").
-		Where("user_id = ? AND is_deleted = ?", userID, false).
+		Preload("Days.Groups").
+		Preload("Weeks", orderPlanWeeks).
+		Where("user_id = ? AND is_active = ? AND is_deleted = ?", userID, true, false).
 		First(&plan).Error
 	return plan, err
 }
@@ -111,10 +198,11 @@ func buildEquipmentCondition(equipmentList []string) string {
 	return strings.Join(conditions, " OR ")
 }
 
-func FindSimilarExercises(referenceEx models.Exercise, profile *models.Profile, equipment []string, maxCount int) ([]models.Exercise, error) {
+func FindSimilarExercises(referenceEx models.Exercise, profile *models.Profile, equipment []string, maxCount int, filter ExerciseFilter) ([]models.Exercise, error) {
 	query := config.DB.Model(&models.Exercise{}).
 		Where("id != ? AND body_part = ? AND is_deleted = ?", referenceEx.ID, referenceEx.BodyPart, false).
 		Limit(maxCount)
+	query = filter.apply(query)
 
 	if len(equipment) > 0 {
 		equipCond := buildEquipmentCondition(equipment)
@@ -133,9 +221,11 @@ func FindExercisesByBodyPartsAndEquipment(
 	bodyParts []string,
 	equipment []string,
 	excludeIDs []uint64,
+	filter ExerciseFilter,
 ) ([]models.Exercise, error) {
 	query := db.Model(&models.Exercise{}).
 		Where("is_deleted = ?", false)
+	query = filter.apply(query)
 
 	// Filter bodypart
 	if len(bodyParts) > 0 {
@@ -159,6 +249,13 @@ func FindExercisesByBodyPartsAndEquipment(
 	return result, nil
 }
 
+func UpdatePlanExerciseSelectionReason(tx *gorm.DB, planExerciseID uint64, reasonJSON string) error {
+	return tx.
+		Model(&models.WorkoutPlanExercise{}).
+		Where("id = ?", planExerciseID).
+		Update("selection_reason_json", reasonJSON).Error
+}
+
 func UpdateExerciseInPlanExercise(tx *gorm.DB, planExerciseID uint64, newExerciseID uint64) error {
 	return tx.
 		Model(&models.WorkoutPlanExercise{}).
@@ -170,7 +267,10 @@ func UpdateWorkoutPlanExerciseReps(tx *gorm.DB, exerciseID uint64, newReps int)
 	err := tx.
 		Model(&models.WorkoutPlanExercise{}).
 		Where("id = ?", exerciseID).
-		Update("reps", newReps).Error
+		Updates(map[string]interface{}{
+			"reps":          newReps,
+			"progressed_at": time.Now(),
+		}).Error
 	if err != nil {
 		return fmt.Errorf("failed to update reps: %w", err)
 	}
@@ -194,3 +294,72 @@ func GetWorkoutPlanExerciseByDayID(dayID uint64) ([]models.WorkoutPlanExercise,
 	}
 	return exercises, nil
 }
+
+func GetWorkoutPlanExerciseByID(planExerciseID uint64) (models.WorkoutPlanExercise, error) {
+	var exercise models.WorkoutPlanExercise
+	err := config.DB.Where("id = ?", planExerciseID).First(&exercise).Error
+	if err != nil {
+		return exercise, fmt.Errorf("workout plan exercise not found")
+	}
+	return exercise, nil
+}
+
+func GetWorkoutPlanDaysByIDs(ids []uint64) (map[uint64]models.WorkoutPlanDay, error) {
+	var days []models.WorkoutPlanDay
+	if err := config.DB.Where("id IN ?", ids).Find(&days).Error; err != nil {
+		return nil, err
+	}
+
+	dayMap := make(map[uint64]models.WorkoutPlanDay)
+	for _, day := range days {
+		dayMap[day.ID] = day
+	}
+	return dayMap, nil
+}
+
+func UpdateWorkoutPlanExercisePrescription(tx *gorm.DB, planExerciseID uint64, reps int, sets int, load float64) error {
+	return tx.
+		Model(&models.WorkoutPlanExercise{}).
+		Where("id = ?", planExerciseID).
+		Updates(map[string]interface{}{
+			"reps":          reps,
+			"sets":          sets,
+			"load":          load,
+			"progressed_at": time.Now(),
+		}).Error
+}
+
+func UpdateWorkoutPlanExerciseTargets(tx *gorm.DB, planExerciseID uint64, restSeconds int, tempo string, targetRPE float64, targetRIR int) error {
+	return tx.
+		Model(&models.WorkoutPlanExercise{}).
+		Where("id = ?", planExerciseID).
+		Updates(map[string]interface{}{
+			"rest_seconds": restSeconds,
+			"tempo":        tempo,
+			"target_rpe":   targetRPE,
+			"target_rir":   targetRIR,
+		}).Error
+}
+
+func UpdateWorkoutPlanDayType(tx *gorm.DB, dayID uint64, dayType string) error {
+	return tx.
+		Model(&models.WorkoutPlanDay{}).
+		Where("id = ?", dayID).
+		Update("day_type", dayType).Error
+}
+
+// GetPlanExerciseCopies returns the plan exercises with the given IDs and every copy made of them.
+func GetPlanExerciseCopies(originIDs []uint64) ([]models.WorkoutPlanExercise, error) {
+	var exercises []models.WorkoutPlanExercise
+	err := config.DB.
+		Where("id IN ? OR origin_plan_exercise_id IN ?", originIDs, originIDs).
+		Find(&exercises).Error
+	return exercises, err
+}
+
+func DismissProgressionProposalTx(tx *gorm.DB, planExerciseID uint64) error {
+	return tx.
+		Model(&models.WorkoutPlanExercise{}).
+		Where("id = ?", planExerciseID).
+		Update("proposal_dismissed_at", time.Now()).Error
+}
@@ -0,0 +1,123 @@
+package repositories
+
+import (
+	"time"
+	"wellnesspath/config"
+	"wellnesspath/models"
+
+	"gorm.io/gorm"
+)
+
+func CreateWorkoutSessionTx(tx *gorm.DB, session *models.WorkoutSession) error {
+	return tx.Create(session).Error
+}
+
+func CreateWorkoutSessionSetTx(tx *gorm.DB, set *models.WorkoutSessionSet) error {
+	return tx.Create(set).Error
+}
+
+func GetWorkoutSessionByID(userID uint64, sessionID uint64) (models.WorkoutSession, error) {
+	var session models.WorkoutSession
+	err := config.DB.
+		Preload("Sets", func(db *gorm.DB) *gorm.DB {
+			return db.Order("completed_at, id")
+		}).
+		Where("id = ? AND user_id = ? AND is_deleted = ?", sessionID, userID, false).
+		First(&session).Error
+	return session, err
+}
+
+// GetWorkoutSessionsByUserID lists the user's sessions, optionally only those logged against one plan version.
+func GetWorkoutSessionsByUserID(userID uint64, planID uint64) ([]models.WorkoutSession, error) {
+	var sessions []models.WorkoutSession
+	query := config.DB.
+		Preload("Sets", func(db *gorm.DB) *gorm.DB {
+			return db.Order("completed_at, id")
+		}).
+		Where("user_id = ? AND is_deleted = ?", userID, false)
+
+	if planID != 0 {
+		query = query.Where("plan_id = ?", planID)
+	}
+
+	err := query.Order("started_at DESC").Find(&sessions).Error
+	return sessions, err
+}
+
+func CountSessionsByPlanID(userID uint64) (map[uint64]int, error) {
+	var rows []struct {
+		PlanID uint64
+		Total  int
+	}
+	err := config.DB.Model(&models.WorkoutSession{}).
+		Select("plan_id, COUNT(*) AS total").
+		Where("user_id = ? AND is_deleted = ?", userID, false).
+		Group("plan_id").
+		Scan(&rows).Error
+	if err != nil {
+		return nil, err
+	}
+
+	counts := make(map[uint64]int)
+	for _, row := range rows {
+		counts[row.PlanID] = row.Total
+	}
+	return counts, nil
+}
+
+func CountSessionsForPlanTx(tx *gorm.DB, planID uint64) (int64, error) {
+	var count int64
+	err := tx.Model(&models.WorkoutSession{}).
+		Where("plan_id = ? AND is_deleted = ?", planID, false).
+		Count(&count).Error
+	return count, err
+}
+
+func GetInProgressSessionByUserID(userID uint64) (models.WorkoutSession, error) {
+	var session models.WorkoutSession
+	err := config.DB.
+		Where("user_id = ? AND status = ? AND is_deleted = ?", userID, models.SessionStatusInProgress, false).
+		First(&session).Error
+	return session, err
+}
+
+func CountSessionSetsForPlanExercise(tx *gorm.DB, sessionID uint64, planExerciseID uint64) (int64, error) {
+	var count int64
+	err := tx.Model(&models.WorkoutSessionSet{}).
+		Where("session_id = ? AND plan_exercise_id = ?", sessionID, planExerciseID).
+		Count(&count).Error
+	return count, err
+}
+
+func FinishWorkoutSessionTx(tx *gorm.DB, session *models.WorkoutSession) error {
+	return tx.Model(session).Updates(map[string]interface{}{
+		"status":      session.Status,
+		"finished_at": session.FinishedAt,
+		"note":        session.Note,
+	}).Error
+}
+
+func GetCompletedSessionSetsByPlanExerciseIDs(userID uint64, planExerciseIDs []uint64) ([]models.WorkoutSessionSet, error) {
+	var sets []models.WorkoutSessionSet
+	err := config.DB.
+		Joins("JOIN workout_sessions ON workout_sessions.id = workout_session_sets.session_id").
+		Where("workout_sessions.user_id = ? AND workout_sessions.status = ? AND workout_sessions.is_deleted = ?",
+			userID, models.SessionStatusCompleted, false).
+		Where("workout_session_sets.plan_exercise_id IN ?", planExerciseIDs).
+		Order("workout_session_sets.created_at DESC, workout_session_sets.id DESC").
+		Find(&sets).Error
+	return sets, err
+}
+
+// GetCompletedSessionSetsInRange returns the sets of the user's completed sessions logged in [from, to),
+// whichever plan version they were logged against.
+func GetCompletedSessionSetsInRange(userID uint64, from time.Time, to time.Time) ([]models.WorkoutSessionSet, error) {
+	var sets []models.WorkoutSessionSet
+	err := config.DB.
+		Joins("JOIN workout_sessions ON workout_sessions.id = workout_session_sets.session_id").
+		Where("workout_sessions.user_id = ? AND workout_sessions.status = ? AND workout_sessions.is_deleted = ?",
+			userID, models.SessionStatusCompleted, false).
+		Where("workout_session_sets.completed_at >= ? AND workout_session_sets.completed_at < ?", from, to).
+		Find(&sets).Error
+	return sets, err
+}
@@ -0,0 +1,130 @@
+package repositories
+
+import (
+	"wellnesspath/config"
+	"wellnesspath/models"
+
+	"gorm.io/gorm"
+	"gorm.io/gorm/clause"
+)
+
+// preloadSplitFocuses orders focuses by their position; "order" is quoted by the dialect since it is a keyword.
+func preloadSplitFocuses(db *gorm.DB) *gorm.DB {
+	return db.Order(clause.OrderByColumn{Column: clause.Column{Name: "order"}})
+}
+
+// GetSplitDefinitionsForUser returns the global splits followed by the user's own splits.
+func GetSplitDefinitionsForUser(userID uint64) ([]models.SplitDefinition, error) {
+	var splits []models.SplitDefinition
+	err := config.DB.
+		Preload("Focuses", preloadSplitFocuses).
+		Where("(owner_id = ? OR owner_id IS NULL) AND is_deleted = ?", userID, false).
+		Order("CASE WHEN owner_id IS NULL THEN 0 ELSE 1 END, name").
+		Find(&splits).Error
+	return splits, err
+}
+
+func GetGlobalSplitDefinitions() ([]models.SplitDefinition, error) {
+	var splits []models.SplitDefinition
+	err := config.DB.
+		Preload("Focuses", preloadSplitFocuses).
+		Where("owner_id IS NULL AND is_deleted = ?", false).
+		Order("name").
+		Find(&splits).Error
+	return splits, err
+}
+
+// GetSplitDefinitionByName looks the name up among the user's own splits first, then the global ones.
+func GetSplitDefinitionByName(userID uint64, name string) (models.SplitDefinition, error) {
+	var split models.SplitDefinition
+	err := config.DB.
+		Preload("Focuses", preloadSplitFocuses).
+		Where("LOWER(name) = LOWER(?) AND (owner_id = ? OR owner_id IS NULL) AND is_deleted = ?", name, userID, false).
+		Order("CASE WHEN owner_id IS NULL THEN 1 ELSE 0 END").
+		First(&split).Error
+	return split, err
+}
+
+func GetSplitDefinitionByID(splitID uint64) (models.SplitDefinition, error) {
+	var split models.SplitDefinition
+	err := config.DB.
+		Preload("Focuses", preloadSplitFocuses).
+		Where("id = ? AND is_deleted = ?", splitID, false).
+		First(&split).Error
+	return split, err
+}
+
+// SplitDefinitionNameExists checks for another live split with the same name and owner (nil for global).
+func SplitDefinitionNameExists(ownerID *uint64, name string, excludeID uint64) (bool, error) {
+	query := config.DB.
+		Model(&models.SplitDefinition{}).
+		Where("LOWER(name) = LOWER(?) AND id <> ? AND is_deleted = ?", name, excludeID, false)
+	if ownerID == nil {
+		query = query.Where("owner_id IS NULL")
+	} else {
+		query = query.Where("owner_id = ?", *ownerID)
+	}
+
+	var count int64
+	err := query.Count(&count).Error
+	return count > 0, err
+}
+
+func CreateSplitDefinitionTx(tx *gorm.DB, split *models.SplitDefinition) error {
+	return tx.Create(split).Error
+}
+
+// ReplaceSplitDefinitionTx updates the split's fields and swaps its focuses for split.Focuses.
+func ReplaceSplitDefinitionTx(tx *gorm.DB, split *models.SplitDefinition) error {
+	err := tx.
+		Model(&models.SplitDefinition{}).
+		Where("id = ?", split.ID).
+		Updates(map[string]interface{}{
+			"name":             split.Name,
+			"description":      split.Description,
+			"min_frequency":    split.MinFrequency,
+			"max_frequency":    split.MaxFrequency,
+			"full_cycles_only": split.FullCyclesOnly,
+		}).Error
+	if err != nil {
+		return err
+	}
+
+	if err := tx.Where("split_id = ?", split.ID).Delete(&models.SplitFocus{}).Error; err != nil {
+		return err
+	}
+	for i := range split.Focuses {
+		split.Focuses[i].SplitID = split.ID
+	}
+	if len(split.Focuses) == 0 {
+		return nil
+	}
+	return tx.Create(&split.Focuses).Error
+}
+
+func DeleteSplitDefinition(splitID uint64) error {
+	return config.DB.
+		Model(&models.SplitDefinition{}).
+		Where("id = ?", splitID).
+		Update("is_deleted", true).Error
+}
+
+// GetDistinctBodyParts lists the body parts that exist in the exercise catalogue.
+func GetDistinctBodyParts() ([]string, error) {
+	var bodyParts []string
+	err := config.DB.
+		Model(&models.Exercise{}).
+		Where("is_deleted = ?", false).
+		Distinct("body_part").
+		Pluck("body_part", &bodyParts).Error
+	return bodyParts, err
+}
+
+func CountProfilesBySplitType(splitType string) (int64, error) {
+	var count int64
+	err := config.DB.
+		Model(&models.Profile{}).
+		Where("LOWER(split_type) = LOWER(?)", splitType).
+		Count(&count).Error
+	return count, err
+}
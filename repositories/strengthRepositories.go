@@ -0,0 +1,45 @@
+package repositories
+
+import (
+	"wellnesspath/config"
+	"wellnesspath/models"
+
+	"gorm.io/gorm"
+)
+
+// GetLoadedSessionSetsByUserID returns every set with load the user logged in a session that was not deleted.
+func GetLoadedSessionSetsByUserID(userID uint64) ([]models.WorkoutSessionSet, error) {
+	var sets []models.WorkoutSessionSet
+	err := config.DB.
+		Joins("JOIN workout_sessions ON workout_sessions.id = workout_session_sets.session_id").
+		Where("workout_sessions.user_id = ? AND workout_sessions.is_deleted = ?", userID, false).
+		Where("workout_session_sets.load > 0 AND workout_session_sets.reps > 0").
+		Order("workout_session_sets.completed_at, workout_session_sets.id").
+		Find(&sets).Error
+	return sets, err
+}
+
+// GetLoadedSessionSetsForExercise returns the user's sets with load for one exercise, leaving out excludeSetID.
+func GetLoadedSessionSetsForExercise(tx *gorm.DB, userID uint64, exerciseID uint64, excludeSetID uint64) ([]models.WorkoutSessionSet, error) {
+	var sets []models.WorkoutSessionSet
+	err := tx.
+		Joins("JOIN workout_sessions ON workout_sessions.id = workout_session_sets.session_id").
+		Where("workout_sessions.user_id = ? AND workout_sessions.is_deleted = ?", userID, false).
+		Where("workout_session_sets.exercise_id = ? AND workout_session_sets.id <> ?", exerciseID, excludeSetID).
+		Where("workout_session_sets.load > 0 AND workout_session_sets.reps > 0").
+		Find(&sets).Error
+	return sets, err
+}
+
+func CreatePersonalRecordTx(tx *gorm.DB, record *models.PersonalRecord) error {
+	return tx.Create(record).Error
+}
+
+func GetPersonalRecordsByUserID(userID uint64) ([]models.PersonalRecord, error) {
+	var records []models.PersonalRecord
+	err := config.DB.
+		Where("user_id = ?", userID).
+		Order("achieved_at DESC, id DESC").
+		Find(&records).Error
+	return records, err
+}
@@ -24,3 +24,18 @@ func DeleteUserByID(tx *gorm.DB, userID uint64) error {
 		Where("id = ? AND is_deleted = false", userID).
 		Update("is_deleted", true).Error
 }
+
+func GetUserByCalendarToken(tx *gorm.DB, token string) (*models.User, error) {
+	var user models.User
+	if err := tx.Where("calendar_token = ? AND is_deleted = ?", token, false).First(&user).Error; err != nil {
+		return nil, err
+	}
+	return &user, nil
+}
+
+func UpdateUserCalendarToken(tx *gorm.DB, userID uint64, token string) error {
+	return tx.
+		Model(&models.User{}).
+		Where("id = ?", userID).
+		Update("calendar_token", token).Error
+}
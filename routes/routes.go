@@ -17,6 +17,9 @@ func SetupRouter() *gin.Engine {
 	router.POST("/login", controllers.Login)
 	router.POST("/register", controllers.Register)
 
+	// Calendar feeds authenticate with the per-user token in the URL
+	router.GET("/calendar/:token", controllers.GetCalendarFeed)
+
 	// Protected routes
 	protected := router.Group("/protected")
 	protected.Use(middleware.AuthenticateJWT())
@@ -38,6 +41,7 @@ func SetupRouter() *gin.Engine {
 			profile.GET("", controllers.GetProfile)
 			profile.PUT("", controllers.UpdateProfile)
 			profile.DELETE("", controllers.DeleteProfile)
+			profile.GET("/limitations/options", controllers.GetLimitationOptions)
 		}
 
 		exercise := protected.Group("/exercises")
@@ -45,17 +49,34 @@ func SetupRouter() *gin.Engine {
 			exercise.GET("", controllers.GetAllExercises)
 			exercise.GET("/:id", controllers.GetExerciseByID)
 			exercise.GET("/video", controllers.GetExerciseVideo)
+			exercise.GET("/preferences", controllers.GetExercisePreferences)
+			exercise.PUT("/:id/preference", controllers.SetExercisePreference)
+			exercise.DELETE("/:id/preference", controllers.RemoveExercisePreference)
 		}
 
 		plan := protected.Group("/plans")
 		{
 			plan.POST("/generate", controllers.GenerateWorkoutPlan)
+			plan.POST("/shuffle", controllers.ShuffleWorkoutPlan)
+			plan.POST("/preview", controllers.PreviewPlanRegeneration)
+			plan.POST("/drafts/:id/apply", controllers.ApplyPlanDraft)
 			plan.GET("", controllers.GetPlanByUserID)
 			plan.GET("/today", controllers.GetWorkoutToday)
+			plan.GET("/day", controllers.GetWorkoutByDate)
+			plan.GET("/schedule", controllers.GetPlanSchedule)
+			plan.GET("/calendar", controllers.GetCalendarFeedToken)
+			plan.POST("/calendar/rotate", controllers.RotateCalendarFeedToken)
+			plan.GET("/history", controllers.GetPlanHistory)
+			plan.GET("/:id", controllers.GetPlanByID)
+			plan.POST("/:id/activate", controllers.ActivatePlan)
 			plan.DELETE("", controllers.DeletePlan)
 			plan.GET("/recommendations", controllers.GetRecommendedReplacements)
 			plan.PUT("/replace", controllers.ReplaceExercise)
 			plan.PUT("/updatereps", controllers.UpdateExerciseReps)
+			plan.PUT("/targets", controllers.UpdateExerciseTargets)
+			plan.PUT("/days/:dayNumber/type", controllers.UpdateRestDayType)
+			plan.GET("/progression", controllers.GetProgressionProposals)
+			plan.POST("/progression/apply", controllers.ApplyProgression)
 
 			divide := plan.Group("/divide")
 			{
@@ -64,6 +85,53 @@ func SetupRouter() *gin.Engine {
 				divide.POST("/insert", controllers.InsertExercisesToDays)
 			}
 		}
+
+		session := protected.Group("/sessions")
+		{
+			session.POST("", controllers.StartSession)
+			session.GET("", controllers.GetSessions)
+			session.GET("/:id", controllers.GetSessionByID)
+			session.POST("/:id/sets", controllers.LogSessionSet)
+			session.POST("/:id/finish", controllers.FinishSession)
+		}
+
+		analytics := protected.Group("/analytics")
+		{
+			analytics.GET("/volume", controllers.GetVolumeAnalytics)
+		}
+
+		strength := protected.Group("/strength")
+		{
+			strength.GET("/profile", controllers.GetStrengthProfile)
+		}
+
+		split := protected.Group("/splits")
+		{
+			split.GET("", controllers.GetSplits)
+			split.POST("", controllers.CreateSplit)
+			split.PUT("/:id", controllers.UpdateSplit)
+			split.DELETE("/:id", controllers.DeleteSplit)
+		}
+
+		admin := protected.Group("/admin")
+		admin.Use(middleware.RequireAdmin())
+		{
+			adminSplit := admin.Group("/splits")
+			{
+				adminSplit.GET("", controllers.GetGlobalSplits)
+				adminSplit.POST("", controllers.CreateGlobalSplit)
+				adminSplit.PUT("/:id", controllers.UpdateGlobalSplit)
+				adminSplit.DELETE("/:id", controllers.DeleteGlobalSplit)
+			}
+
+			adminFocus := admin.Group("/focuses")
+			{
+				adminFocus.GET("", controllers.GetFocusMappings)
+				adminFocus.POST("", controllers.CreateFocusMapping)
+				adminFocus.PUT("/:id", controllers.UpdateFocusMapping)
+				adminFocus.DELETE("/:id", controllers.DeleteFocusMapping)
+			}
+		}
 	}
 
 	return router
@@ -5,6 +5,7 @@ import (
 	"io"
 	"log"
 	"os"
+	"strconv"
 	"strings"
 	"wellnesspath/config"
 	"wellnesspath/models"
@@ -51,6 +52,7 @@ func SeedExercisesFromFile() error {
 			Name:                   safeGet(record, 0),
 			BodyPart:               safeGet(record, 5),
 			Equipment:              safeGet(record, 7),
+			Rating:                 parseRating(safeGet(record, 8)),
 			Description:            safeGet(record, 9),
 			StepByStepInstructions: safeGet(record, 9),
 			Difficulty:             safeGet(record, 10),
@@ -77,6 +79,15 @@ func SeedExercisesFromFile() error {
 	return nil
 }
 
+// parseRating reads the CSV rating, which uses a decimal comma ("8,6"). Missing ratings become 0.
+func parseRating(value string) float64 {
+	rating, err := strconv.ParseFloat(strings.Replace(value, ",", ".", 1), 64)
+	if err != nil {
+		return 0
+	}
+	return rating
+}
+
 func safeGet(record []string, index int) string {
 	if len(record) > index {
 		return strings.TrimSpace(record[index])
@@ -0,0 +1,57 @@
+package seeder
+
+import (
+	"encoding/json"
+	"fmt"
+	"log"
+	"os"
+	"sort"
+	"wellnesspath/config"
+	"wellnesspath/helpers"
+	"wellnesspath/models"
+	"wellnesspath/repositories"
+)
+
+// SeedFocusMappingsFromFile inserts the focus mappings from focus_mappings.json that are not in the
+// database yet. Existing rows are left alone so admin edits survive restarts.
+func SeedFocusMappingsFromFile() error {
+	if config.DB == nil {
+		log.Fatal("❌ Database is not connected.")
+	}
+
+	data, err := os.ReadFile("focus_mappings.json")
+	if err != nil {
+		return fmt.Errorf("failed to open focus mappings: %w", err)
+	}
+
+	var mappings map[string][]string
+	if err := json.Unmarshal(data, &mappings); err != nil {
+		return fmt.Errorf("failed to parse focus mappings: %w", err)
+	}
+
+	focuses := make([]string, 0, len(mappings))
+	for focus := range mappings {
+		focuses = append(focuses, focus)
+	}
+	sort.Strings(focuses)
+
+	insertCount := 0
+	for _, focus := range focuses {
+		exists, err := repositories.FocusMappingExists(focus, 0)
+		if err != nil {
+			return err
+		}
+		if exists {
+			continue
+		}
+
+		mapping := models.FocusMapping{Focus: focus, BodyPartsJSON: helpers.EncodeBodyParts(mappings[focus])}
+		if err := repositories.CreateFocusMapping(&mapping); err != nil {
+			return fmt.Errorf("failed to insert focus mapping %q: %w", focus, err)
+		}
+		insertCount++
+	}
+
+	log.Printf("✅ Finished seeding focus mappings: %d inserted.\n", insertCount)
+	return nil
+}
@@ -0,0 +1,68 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"wellnesspath/config"
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/repositories"
+)
+
+type AnalyticsService struct{}
+
+// GetVolume reports prescribed and performed sets and tonnage per body part for each of the last
+// weeks weeks. Prescribed work comes from the active plan; performed sets include sessions logged
+// against earlier plan versions. Exercise details are loaded in one batch for both sources.
+func (s *AnalyticsService) GetVolume(userID uint64, weeks int) (dto.VolumeAnalyticsOutput, error) {
+	plan, err := repositories.GetWorkoutPlanWithDetails(userID)
+	if err != nil {
+		return dto.VolumeAnalyticsOutput{}, fmt.Errorf("user has no active workout plan")
+	}
+
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return dto.VolumeAnalyticsOutput{}, fmt.Errorf("failed to retrieve profile: %w", err)
+	}
+	loc := helpers.LoadUserLocation(profile.Timezone)
+
+	weekStarts := helpers.AnalyticsWeekStarts(helpers.TodayIn(loc), weeks)
+	from := weekStarts[0]
+	to := weekStarts[len(weekStarts)-1].AddDate(0, 0, 6)
+
+	fromTime := time.Date(from.Year(), from.Month(), from.Day(), 0, 0, 0, 0, loc)
+	toTime := time.Date(to.Year(), to.Month(), to.Day()+1, 0, 0, 0, 0, loc)
+	performed, err := repositories.GetCompletedSessionSetsInRange(userID, fromTime, toTime)
+	if err != nil {
+		return dto.VolumeAnalyticsOutput{}, fmt.Errorf("failed to retrieve logged sets: %w", err)
+	}
+
+	uniqueIDs := map[uint64]struct{}{}
+	for _, day := range plan.Days {
+		for _, ex := range day.Exercises {
+			uniqueIDs[ex.ExerciseID] = struct{}{}
+		}
+	}
+	for _, set := range performed {
+		uniqueIDs[set.ExerciseID] = struct{}{}
+	}
+	ids := make([]uint64, 0, len(uniqueIDs))
+	for id := range uniqueIDs {
+		ids = append(ids, id)
+	}
+
+	exMap, err := repositories.GetExercisesByIDs(ids)
+	if err != nil {
+		return dto.VolumeAnalyticsOutput{}, fmt.Errorf("failed to retrieve exercise details: %w", err)
+	}
+
+	scheduled := helpers.BuildSchedule(plan, exMap, from, to)
+	weekly := helpers.AggregateWeeklyVolume(weekStarts, scheduled, performed, exMap, loc)
+
+	return dto.VolumeAnalyticsOutput{
+		PlanID: plan.ID,
+		Weeks:  weekly,
+		Trends: helpers.BuildVolumeTrends(weekly),
+	}, nil
+}
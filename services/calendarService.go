@@ -0,0 +1,82 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"wellnesspath/config"
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/repositories"
+)
+
+type CalendarService struct{}
+
+// GetFeed returns the user's calendar feed token, creating one on first use.
+func (s *CalendarService) GetFeed(userID uint64) (dto.CalendarFeedResponse, error) {
+	user, err := repositories.GetUserByID(config.DB, userID)
+	if err != nil {
+		return dto.CalendarFeedResponse{}, errors.New("user not found")
+	}
+
+	if user.CalendarToken != "" {
+		return buildCalendarFeedResponse(user.CalendarToken), nil
+	}
+	return s.RotateFeedToken(userID)
+}
+
+// RotateFeedToken replaces the feed token, invalidating any existing subscription URL.
+func (s *CalendarService) RotateFeedToken(userID uint64) (dto.CalendarFeedResponse, error) {
+	token, err := helpers.GenerateFeedToken()
+	if err != nil {
+		return dto.CalendarFeedResponse{}, err
+	}
+
+	tx := config.DB.Begin()
+	if err := repositories.UpdateUserCalendarToken(tx, userID, token); err != nil {
+		tx.Rollback()
+		return dto.CalendarFeedResponse{}, fmt.Errorf("failed to save feed token: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return dto.CalendarFeedResponse{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return buildCalendarFeedResponse(token), nil
+}
+
+// RenderFeed renders the active plan of the user owning token as an iCalendar document.
+func (s *CalendarService) RenderFeed(token string) (string, error) {
+	if token == "" {
+		return "", helpers.NewUnauthorizedError("invalid calendar token")
+	}
+
+	user, err := repositories.GetUserByCalendarToken(config.DB, token)
+	if err != nil {
+		return "", helpers.NewUnauthorizedError("invalid calendar token")
+	}
+
+	plan, err := repositories.GetActiveWorkoutPlanByUserID(user.ID)
+	if err != nil {
+		return "", fmt.Errorf("user has no active workout plan")
+	}
+
+	timezone := helpers.DefaultTimezone
+	if profile, err := repositories.GetProfileByUserID(config.DB, user.ID); err == nil && profile.Timezone != "" {
+		timezone = profile.Timezone
+	}
+
+	exMap, err := getPlanExerciseDetails(plan)
+	if err != nil {
+		return "", fmt.Errorf("failed to retrieve exercise details: %w", err)
+	}
+
+	return helpers.BuildICalendar(plan, exMap, timezone, time.Now()), nil
+}
+
+func buildCalendarFeedResponse(token string) dto.CalendarFeedResponse {
+	return dto.CalendarFeedResponse{
+		Token:    token,
+		FeedPath: "/calendar/" + token + ".ics",
+	}
+}
@@ -0,0 +1,88 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+
+	"wellnesspath/config"
+	"wellnesspath/dto"
+	"wellnesspath/models"
+	"wellnesspath/repositories"
+)
+
+type ExercisePreferenceService struct{}
+
+// GetPreferences lists the user's favourite and blocked exercises, most recently changed first.
+func (s *ExercisePreferenceService) GetPreferences(userID uint64) (dto.ExercisePreferencesResponseDTO, error) {
+	rows, err := repositories.GetExercisePreferencesByUserID(userID)
+	if err != nil {
+		return dto.ExercisePreferencesResponseDTO{}, fmt.Errorf("failed to retrieve exercise preferences: %w", err)
+	}
+
+	ids := make([]uint64, 0, len(rows))
+	for _, row := range rows {
+		ids = append(ids, row.ExerciseID)
+	}
+	exMap, err := repositories.GetExercisesByIDs(ids)
+	if err != nil {
+		return dto.ExercisePreferencesResponseDTO{}, fmt.Errorf("failed to retrieve exercise details: %w", err)
+	}
+
+	response := dto.ExercisePreferencesResponseDTO{
+		Favourites: []dto.ExercisePreferenceItem{},
+		Blocked:    []dto.ExercisePreferenceItem{},
+	}
+	for _, row := range rows {
+		detail, ok := exMap[row.ExerciseID]
+		if !ok {
+			continue
+		}
+		item := dto.ExercisePreferenceItem{ExerciseID: detail.ID, Name: detail.Name, BodyPart: detail.BodyPart}
+		if row.Preference == models.ExercisePreferenceBlocked {
+			response.Blocked = append(response.Blocked, item)
+		} else {
+			response.Favourites = append(response.Favourites, item)
+		}
+	}
+	return response, nil
+}
+
+// SetPreference marks an exercise as favourite or blocked and reports whether the active plan uses it,
+// since blocking only applies to plans generated afterwards.
+func (s *ExercisePreferenceService) SetPreference(userID uint64, exerciseID uint64, req dto.ExercisePreferenceRequest) (dto.SetExercisePreferenceResponseDTO, error) {
+	if _, err := repositories.GetExerciseByID(exerciseID); err != nil {
+		return dto.SetExercisePreferenceResponseDTO{}, errors.New("exercise not found")
+	}
+
+	tx := config.DB.Begin()
+	if err := repositories.SetExercisePreferenceTx(tx, userID, exerciseID, req.Preference); err != nil {
+		tx.Rollback()
+		return dto.SetExercisePreferenceResponseDTO{}, fmt.Errorf("failed to save exercise preference: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return dto.SetExercisePreferenceResponseDTO{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	response := dto.SetExercisePreferenceResponseDTO{ExerciseID: exerciseID, Preference: req.Preference}
+	if plan, err := repositories.GetActiveWorkoutPlanByUserID(userID); err == nil {
+		for _, day := range plan.Days {
+			for _, ex := range day.Exercises {
+				if ex.ExerciseID == exerciseID {
+					response.InActivePlan = true
+				}
+			}
+		}
+	}
+	return response, nil
+}
+
+func (s *ExercisePreferenceService) RemovePreference(userID uint64, exerciseID uint64) error {
+	removed, err := repositories.DeleteExercisePreference(userID, exerciseID)
+	if err != nil {
+		return fmt.Errorf("failed to remove exercise preference: %w", err)
+	}
+	if !removed {
+		return errors.New("exercise preference not found")
+	}
+	return nil
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"log"
+	"strings"
+
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/models"
+	"wellnesspath/repositories"
+)
+
+type FocusMappingService struct{}
+
+func (s *FocusMappingService) GetFocusMappings() ([]dto.FocusMappingResponse, error) {
+	mappings, err := repositories.GetAllFocusMappings()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve focus mappings: %w", err)
+	}
+
+	result := []dto.FocusMappingResponse{}
+	for _, mapping := range mappings {
+		result = append(result, buildFocusMappingResponse(mapping))
+	}
+	return result, nil
+}
+
+func (s *FocusMappingService) CreateFocusMapping(req dto.FocusMappingRequest) (dto.FocusMappingResponse, error) {
+	mapping := models.FocusMapping{}
+	if err := applyFocusMappingRequest(&mapping, req); err != nil {
+		return dto.FocusMappingResponse{}, err
+	}
+
+	if err := repositories.CreateFocusMapping(&mapping); err != nil {
+		return dto.FocusMappingResponse{}, fmt.Errorf("failed to create focus mapping: %w", err)
+	}
+	if err := LoadFocusRegistry(); err != nil {
+		return dto.FocusMappingResponse{}, err
+	}
+	return buildFocusMappingResponse(mapping), nil
+}
+
+func (s *FocusMappingService) UpdateFocusMapping(id uint64, req dto.FocusMappingRequest) (dto.FocusMappingResponse, error) {
+	mapping, err := repositories.GetFocusMappingByID(id)
+	if err != nil {
+		return dto.FocusMappingResponse{}, errors.New("focus mapping not found")
+	}
+
+	previousFocus := mapping.Focus
+	if err := applyFocusMappingRequest(&mapping, req); err != nil {
+		return dto.FocusMappingResponse{}, err
+	}
+	if !strings.EqualFold(previousFocus, mapping.Focus) && isBuiltInFocus(previousFocus) {
+		return dto.FocusMappingResponse{}, helpers.NewBadRequestError(fmt.Sprintf("%q is used by a built-in split; it cannot be renamed", previousFocus))
+	}
+
+	if err := repositories.UpdateFocusMapping(&mapping); err != nil {
+		return dto.FocusMappingResponse{}, fmt.Errorf("failed to update focus mapping: %w", err)
+	}
+	if err := LoadFocusRegistry(); err != nil {
+		return dto.FocusMappingResponse{}, err
+	}
+	return buildFocusMappingResponse(mapping), nil
+}
+
+func (s *FocusMappingService) DeleteFocusMapping(id uint64) error {
+	mapping, err := repositories.GetFocusMappingByID(id)
+	if err != nil {
+		return errors.New("focus mapping not found")
+	}
+	if isBuiltInFocus(mapping.Focus) {
+		return helpers.NewBadRequestError(fmt.Sprintf("%q is used by a built-in split; it cannot be deleted", mapping.Focus))
+	}
+
+	if err := repositories.DeleteFocusMapping(mapping.ID); err != nil {
+		return fmt.Errorf("failed to delete focus mapping: %w", err)
+	}
+	return LoadFocusRegistry()
+}
+
+// LoadFocusRegistry reloads helpers' focus registry from the database and warns about mappings
+// that would make generation fall back to unfocused exercise picks.
+func LoadFocusRegistry() error {
+	mappings, err := repositories.GetAllFocusMappings()
+	if err != nil {
+		return fmt.Errorf("failed to load focus mappings: %w", err)
+	}
+
+	registry := make(map[string][]string, len(mappings))
+	for _, mapping := range mappings {
+		registry[mapping.Focus] = helpers.DecodeBodyParts(mapping.BodyPartsJSON)
+	}
+	helpers.SetFocusMappings(registry)
+
+	for _, focus := range helpers.BuiltInSplitFocusNames() {
+		if len(helpers.GetBodyPartsForFocus(focus)) == 0 {
+			log.Printf("⚠️ Built-in focus %q has no mapped body parts; splits using it cannot be generated", focus)
+		}
+	}
+
+	knownParts, err := repositories.GetDistinctBodyParts()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve body parts: %w", err)
+	}
+	for focus, bodyParts := range registry {
+		for _, part := range bodyParts {
+			if !helpers.Contains(knownParts, part) {
+				log.Printf("⚠️ Focus %q maps to body part %q, which no exercise uses", focus, part)
+			}
+		}
+	}
+	return nil
+}
+
+// applyFocusMappingRequest validates req and copies it onto mapping, normalising body part names
+// to the spelling used in the exercise catalogue.
+func applyFocusMappingRequest(mapping *models.FocusMapping, req dto.FocusMappingRequest) error {
+	focus := strings.TrimSpace(req.Focus)
+	if focus == "" || strings.EqualFold(focus, "Rest") {
+		return helpers.NewBadRequestError("focus needs a name other than Rest")
+	}
+	exists, err := repositories.FocusMappingExists(focus, mapping.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return helpers.NewBadRequestError(fmt.Sprintf("a mapping for %q already exists", focus))
+	}
+
+	knownParts, err := repositories.GetDistinctBodyParts()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve body parts: %w", err)
+	}
+
+	var bodyParts []string
+	for _, part := range req.BodyParts {
+		canonical, ok := matchBodyPart(knownParts, part)
+		if !ok {
+			return helpers.NewBadRequestError(fmt.Sprintf("unknown body part %q", part))
+		}
+		if !helpers.Contains(bodyParts, canonical) {
+			bodyParts = append(bodyParts, canonical)
+		}
+	}
+
+	mapping.Focus = focus
+	mapping.BodyPartsJSON = helpers.EncodeBodyParts(bodyParts)
+	return nil
+}
+
+func isBuiltInFocus(focus string) bool {
+	return helpers.Contains(helpers.BuiltInSplitFocusNames(), focus)
+}
+
+func buildFocusMappingResponse(mapping models.FocusMapping) dto.FocusMappingResponse {
+	return dto.FocusMappingResponse{
+		ID:        mapping.ID,
+		Focus:     mapping.Focus,
+		BodyParts: helpers.DecodeBodyParts(mapping.BodyPartsJSON),
+	}
+}
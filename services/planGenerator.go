@@ -0,0 +1,455 @@
+package services
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"log"
+	"math/rand"
+	"strings"
+	"time"
+
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/models"
+	"wellnesspath/repositories"
+
+	"gorm.io/gorm"
+)
+
+// buildPlanDraft runs plan generation for a profile without writing anything.
+// The same profile, exercise catalogue and seed always produce the same plan.
+// The returned plan, its days and its mesocycleWeeks weeks have no IDs until saved with saveWorkoutPlanTx.
+func buildPlanDraft(userID uint64, profile *models.Profile, seed int64, mesocycleWeeks int) (models.WorkoutPlan, error) {
+	var restDays []int
+	if err := json.Unmarshal([]byte(profile.RestDaysJSON), &restDays); err != nil {
+		return models.WorkoutPlan{}, fmt.Errorf("failed to parse rest days: %w", err)
+	}
+
+	split, err := resolveSplit(userID, profile.SplitType)
+	if err != nil {
+		return models.WorkoutPlan{}, err
+	}
+
+	if err := helpers.ValidateSplitAndRestDays(split, profile.Frequency, restDays); err != nil {
+		return models.WorkoutPlan{}, err
+	}
+
+	prefs, err := loadExercisePreferences(userID)
+	if err != nil {
+		return models.WorkoutPlan{}, err
+	}
+
+	equipment := helpers.DecodeEquipment(profile.EquipmentJSON)
+	exercises, err := repositories.GetExercisesByGoalAndEquipment(profile.Goal, equipment, exerciseFilterForProfile(profile, prefs))
+	if err != nil || len(exercises) == 0 {
+		return models.WorkoutPlan{}, errors.New("no exercises match your profile")
+	}
+
+	plan := models.WorkoutPlan{
+		UserID:    userID,
+		SplitType: split.Name,
+		Goal:      profile.Goal,
+		StartDate: helpers.TodayIn(helpers.LoadUserLocation(profile.Timezone)),
+		Seed:      seed,
+		Weeks:     helpers.BuildPlanWeeks(mesocycleWeeks, profile.Intensity),
+	}
+	rng := helpers.NewPlanRand(seed)
+
+	splitFocuses := split.FocusSequence(profile.Frequency)
+
+	restMap := map[int]bool{}
+	for _, d := range restDays {
+		restMap[d] = true
+	}
+
+	usedExerciseIDs := map[uint64]bool{}
+	volume := helpers.NewVolumePlanner(profile.Goal, profile.Intensity)
+	exMap := exercisesByID(exercises)
+	limitations := helpers.DecodeLimitations(profile.LimitationsJSON)
+	focusIndex := 0
+
+	for dayNum := 1; dayNum <= 7; dayNum++ {
+		if restMap[dayNum] {
+			plan.Days = append(plan.Days, models.WorkoutPlanDay{
+				DayNumber: dayNum,
+				DayType:   models.DayTypeRest,
+				Focus:     "Rest",
+			})
+			continue
+		}
+
+		if focusIndex >= len(splitFocuses) {
+			break
+		}
+		focus := splitFocuses[focusIndex]
+		focusIndex++
+
+		selected, err := selectExercisesForFocus(rng, exercises, exMap, limitations, profile, focus, usedExerciseIDs, volume, prefs.favourites)
+		if err != nil {
+			return models.WorkoutPlan{}, err
+		}
+
+		plan.Days = append(plan.Days, models.WorkoutPlanDay{
+			DayNumber:     dayNum,
+			DayType:       models.DayTypeTraining,
+			Focus:         focus.Name,
+			BodyPartsJSON: helpers.EncodeBodyParts(focus.BodyParts),
+			Exercises:     selected,
+		})
+	}
+
+	helpers.BalanceWeeklySets(plan.Days, exMap, volume.Target)
+	for i := range plan.Days {
+		helpers.FitDayToDuration(&plan.Days[i], exMap, profile.Goal, profile.DurationPerSession, limitations)
+		helpers.GroupDayExercises(&plan.Days[i], exMap, profile.Goal)
+	}
+
+	return plan, nil
+}
+
+func exercisesByID(exercises []models.Exercise) map[uint64]*models.Exercise {
+	exMap := make(map[uint64]*models.Exercise, len(exercises))
+	for i := range exercises {
+		exMap[exercises[i].ID] = &exercises[i]
+	}
+	return exMap
+}
+
+// selectExercisesForFocus picks and prescribes one day's exercises (see helpers.RankExercises),
+// skipping any already used elsewhere in the plan and ending with the goal's cardio item, if any.
+// The day gets as many exercises as fit the session length at their default sets; only the ones
+// kept are marked as used, so the rest stay available to later days.
+func selectExercisesForFocus(rng *rand.Rand, exercises []models.Exercise, exMap map[uint64]*models.Exercise, limitations []dto.Limitation, profile *models.Profile, focus helpers.FocusDefinition, usedExerciseIDs map[uint64]bool, volume *helpers.VolumePlanner, favourites map[uint64]bool) ([]models.WorkoutPlanExercise, error) {
+	focusFallback := false
+	focused := helpers.FilterExercisesByBodyParts(exercises, focus.BodyParts)
+	if len(focused) == 0 {
+		log.Printf("⚠️ No exercises match focus %q (%v); falling back to all exercises", focus.Name, focus.BodyParts)
+		focused = exercises // fallback ke semua
+		focusFallback = true
+	}
+	focused = helpers.ShuffleExercises(rng, focused)
+
+	var conditioning *models.WorkoutPlanExercise
+	strengthMinutes := profile.DurationPerSession
+	if cardio, ok := helpers.PickConditioningExercise(rng, exercises, profile.Goal, usedExerciseIDs); ok {
+		if item, ok := helpers.NewConditioningItem(cardio, profile.Goal, profile.Intensity); ok {
+			usedExerciseIDs[cardio.ID] = true
+			conditioning = &item
+			strengthMinutes -= helpers.EstimatePlanExerciseSeconds(item) / 60
+		}
+	}
+
+	reps := helpers.DetermineReps(profile.Intensity, profile.Goal, profile.BMICategory)
+	targets := helpers.DefaultTrainingTargets(profile.Goal, profile.Intensity)
+	exerciseCount := helpers.CalculateMaxExercises(strengthMinutes, reps, targets)
+	validParts := focus.BodyParts
+
+	rank := func(count int) []helpers.RankedExercise {
+		return helpers.RankExercises(focused, profile, validParts, count, usedExerciseIDs, volume, favourites)
+	}
+	fits := func(picks []helpers.RankedExercise) bool {
+		day := models.WorkoutPlanDay{
+			DayType:       models.DayTypeTraining,
+			Focus:         focus.Name,
+			BodyPartsJSON: helpers.EncodeBodyParts(focus.BodyParts),
+		}
+		for i, pick := range picks {
+			ex := models.WorkoutPlanExercise{
+				ExerciseID: pick.Exercise.ID,
+				Order:      i + 1,
+				Mode:       models.ExerciseModeReps,
+				Reps:       reps,
+				Sets:       helpers.DefaultSetsPerExercise,
+			}
+			helpers.ApplyTrainingTargets(&ex, targets)
+			day.Exercises = append(day.Exercises, ex)
+		}
+		if conditioning != nil {
+			item := *conditioning
+			item.Order = len(picks) + 1
+			day.Exercises = append(day.Exercises, item)
+		}
+		return helpers.DayFitsDuration(day, exMap, profile.Goal, profile.DurationPerSession, limitations)
+	}
+
+	ranked := rank(exerciseCount)
+	if profile.DurationPerSession > 0 {
+		for exerciseCount > helpers.MinExercisesPerSession && !fits(ranked) {
+			exerciseCount--
+			ranked = rank(exerciseCount)
+		}
+		for exerciseCount < helpers.MaxExercisesPerSession {
+			more := rank(exerciseCount + 1)
+			if len(more) <= len(ranked) || !fits(more) {
+				break
+			}
+			exerciseCount++
+			ranked = more
+		}
+	}
+	if len(ranked) == 0 {
+		return nil, fmt.Errorf("no suitable exercises found for focus %s", focus.Name)
+	}
+
+	coveredParts := map[string]bool{}
+	planExercises := make([]models.WorkoutPlanExercise, 0, len(ranked))
+	for i, pick := range ranked {
+		ex := pick.Exercise
+		usedExerciseIDs[ex.ID] = true
+		volume.Add(ex.BodyPart, helpers.DefaultSetsPerExercise)
+
+		reason := helpers.ExplainSelection(pick, focusFallback, profile, focus, coveredParts)
+		coveredParts[strings.ToLower(ex.BodyPart)] = true
+
+		planExercise := models.WorkoutPlanExercise{
+			ExerciseID:          ex.ID,
+			Order:               i + 1,
+			Mode:                models.ExerciseModeReps,
+			Reps:                reps,
+			Sets:                helpers.DefaultSetsPerExercise,
+			SelectionReasonJSON: helpers.EncodeSelectionReason(reason),
+		}
+		helpers.ApplyTrainingTargets(&planExercise, targets)
+		planExercises = append(planExercises, planExercise)
+	}
+
+	if conditioning != nil {
+		conditioning.Order = len(planExercises) + 1
+		planExercises = append(planExercises, *conditioning)
+	}
+	return planExercises, nil
+}
+
+// saveWorkoutPlanTx inserts a generated plan with its days and exercises, filling in the new IDs.
+func saveWorkoutPlanTx(tx *gorm.DB, plan *models.WorkoutPlan) error {
+	days, weeks := plan.Days, plan.Weeks
+	plan.Days, plan.Weeks = nil, nil
+	if err := repositories.CreateWorkoutPlanTx(tx, plan); err != nil {
+		return err
+	}
+
+	for i := range weeks {
+		weeks[i].PlanID = plan.ID
+	}
+	if len(weeks) > 0 {
+		if err := repositories.CreateWorkoutPlanWeeksTx(tx, weeks); err != nil {
+			return fmt.Errorf("failed to save plan weeks: %w", err)
+		}
+	}
+	plan.Weeks = weeks
+
+	for i := range days {
+		days[i].PlanID = plan.ID
+		exercises, groups := days[i].Exercises, days[i].Groups
+		days[i].Exercises, days[i].Groups = nil, nil
+		if err := repositories.CreateWorkoutPlanDayTx(tx, &days[i]); err != nil {
+			return err
+		}
+
+		for j := range groups {
+			groups[j].DayID = days[i].ID
+		}
+		if len(groups) > 0 {
+			if err := repositories.CreateWorkoutPlanGroupsTx(tx, groups); err != nil {
+				return fmt.Errorf("failed to save exercise groups for day %d: %w", days[i].DayNumber, err)
+			}
+		}
+		days[i].Groups = groups
+
+		for j := range exercises {
+			exercises[j].DayID = days[i].ID
+		}
+		if len(exercises) > 0 {
+			if err := repositories.CreateWorkoutPlanExercisesBatchTx(tx, exercises); err != nil {
+				return fmt.Errorf("batch insert failed for day %d: %w", days[i].DayNumber, err)
+			}
+		}
+		days[i].Exercises = exercises
+	}
+
+	plan.Days = days
+	return nil
+}
+
+// exercisePreferences holds the exercises a user marked as favourite or blocked.
+type exercisePreferences struct {
+	favourites map[uint64]bool
+	blocked    map[uint64]bool
+}
+
+func loadExercisePreferences(userID uint64) (exercisePreferences, error) {
+	prefs := exercisePreferences{favourites: map[uint64]bool{}, blocked: map[uint64]bool{}}
+
+	rows, err := repositories.GetExercisePreferencesByUserID(userID)
+	if err != nil {
+		return prefs, fmt.Errorf("failed to retrieve exercise preferences: %w", err)
+	}
+	for _, row := range rows {
+		switch row.Preference {
+		case models.ExercisePreferenceFavourite:
+			prefs.favourites[row.ExerciseID] = true
+		case models.ExercisePreferenceBlocked:
+			prefs.blocked[row.ExerciseID] = true
+		}
+	}
+	return prefs, nil
+}
+
+// exerciseFilterForProfile turns the profile's "avoid" limitations and the user's blocked
+// exercises into a catalogue filter. "limit" limitations are handled while ranking (see helpers.RankExercises).
+func exerciseFilterForProfile(profile *models.Profile, prefs exercisePreferences) repositories.ExerciseFilter {
+	var filter repositories.ExerciseFilter
+	for id := range prefs.blocked {
+		filter.ExcludedIDs = append(filter.ExcludedIDs, id)
+	}
+	for _, limitation := range helpers.DecodeLimitations(profile.LimitationsJSON) {
+		if limitation.Severity != helpers.LimitationSeverityAvoid {
+			continue
+		}
+		switch limitation.Type {
+		case helpers.LimitationTypeBodyPart:
+			filter.ExcludedBodyParts = append(filter.ExcludedBodyParts, limitation.Target)
+		case helpers.LimitationTypeMovement:
+			filter.ExcludedNameKeywords = append(filter.ExcludedNameKeywords, helpers.MovementKeywords(limitation.Target)...)
+		}
+	}
+	return filter
+}
+
+// keepActiveRecoveryDays carries the active-recovery choice of previous over to the rest days
+// of a regenerated plan that fall on the same weekday.
+func keepActiveRecoveryDays(plan *models.WorkoutPlan, previous models.WorkoutPlan) {
+	recovery := map[int]bool{}
+	for _, day := range previous.Days {
+		if day.DayType == models.DayTypeActiveRecovery {
+			recovery[day.DayNumber] = true
+		}
+	}
+	for i := range plan.Days {
+		if plan.Days[i].DayType == models.DayTypeRest && recovery[plan.Days[i].DayNumber] {
+			plan.Days[i].DayType = models.DayTypeActiveRecovery
+		}
+	}
+}
+
+// keepProgressedLoads carries the loads progressed in previous over to the same exercises in a
+// regenerated plan, which are generated without a load.
+func keepProgressedLoads(plan *models.WorkoutPlan, previous models.WorkoutPlan) {
+	loads := map[uint64]float64{}
+	for _, day := range previous.Days {
+		for _, ex := range day.Exercises {
+			if helpers.IsRepsBased(ex) && ex.Load > loads[ex.ExerciseID] {
+				loads[ex.ExerciseID] = ex.Load
+			}
+		}
+	}
+	for i := range plan.Days {
+		for j := range plan.Days[i].Exercises {
+			ex := &plan.Days[i].Exercises[j]
+			if helpers.IsRepsBased(*ex) && ex.Load == 0 {
+				ex.Load = loads[ex.ExerciseID]
+			}
+		}
+	}
+}
+
+// mesocycleLength keeps the mesocycle length of plan when regenerating it.
+func mesocycleLength(plan models.WorkoutPlan) int {
+	if len(plan.Weeks) == 0 {
+		return helpers.DefaultMesocycleWeeks
+	}
+	return len(plan.Weeks)
+}
+
+// publishWorkoutPlanTx makes plan the user's active plan under the next version number.
+// New plans are inserted; saved drafts are promoted in place.
+func publishWorkoutPlanTx(tx *gorm.DB, plan *models.WorkoutPlan) error {
+	version, err := repositories.GetNextWorkoutPlanVersionTx(tx, plan.UserID)
+	if err != nil {
+		return fmt.Errorf("failed to determine plan version: %w", err)
+	}
+	if err := repositories.DeactivateWorkoutPlansByUserIDTx(tx, plan.UserID); err != nil {
+		return fmt.Errorf("failed to deactivate existing workout plans: %w", err)
+	}
+
+	plan.Version = version
+	plan.IsActive = true
+
+	if plan.ID == 0 {
+		return saveWorkoutPlanTx(tx, plan)
+	}
+
+	plan.IsDraft = false
+	if err := repositories.PublishWorkoutPlanDraftTx(tx, plan); err != nil {
+		return fmt.Errorf("failed to publish workout plan: %w", err)
+	}
+	return nil
+}
+
+// editableActivePlanTx returns the version of the active plan an edit may rewrite, and the IDs its
+// plan exercises have in that version. A version sessions were logged against is kept as it was
+// trained: it is copied into a new active version and the copy is returned (see models.WorkoutPlan).
+func editableActivePlanTx(tx *gorm.DB, plan models.WorkoutPlan) (models.WorkoutPlan, map[uint64]uint64, error) {
+	ids := make(map[uint64]uint64)
+
+	sessions, err := repositories.CountSessionsForPlanTx(tx, plan.ID)
+	if err != nil {
+		return models.WorkoutPlan{}, nil, fmt.Errorf("failed to count sessions of workout plan: %w", err)
+	}
+	if sessions == 0 {
+		for _, day := range plan.Days {
+			for _, ex := range day.Exercises {
+				ids[ex.ID] = ex.ID
+			}
+		}
+		return plan, ids, nil
+	}
+	if err := ensureNoSessionInProgress(plan.UserID); err != nil {
+		return models.WorkoutPlan{}, nil, err
+	}
+
+	copied := models.WorkoutPlan{
+		UserID:     plan.UserID,
+		SplitType:  plan.SplitType,
+		Goal:       plan.Goal,
+		StartDate:  plan.StartDate,
+		BasePlanID: plan.ID,
+		Seed:       plan.Seed,
+	}
+	for _, week := range plan.Weeks {
+		week.ID, week.PlanID = 0, 0
+		week.CreatedAt, week.UpdatedAt = time.Time{}, time.Time{}
+		copied.Weeks = append(copied.Weeks, week)
+	}
+	for _, day := range plan.Days {
+		dayCopy := day
+		dayCopy.ID, dayCopy.PlanID = 0, 0
+		dayCopy.CreatedAt, dayCopy.UpdatedAt = time.Time{}, time.Time{}
+		dayCopy.Exercises, dayCopy.Groups = nil, nil
+		for _, group := range day.Groups {
+			group.ID, group.DayID = 0, 0
+			group.CreatedAt, group.UpdatedAt = time.Time{}, time.Time{}
+			dayCopy.Groups = append(dayCopy.Groups, group)
+		}
+		for _, ex := range day.Exercises {
+			if ex.OriginPlanExerciseID == 0 {
+				ex.OriginPlanExerciseID = ex.ID
+			}
+			ex.ID, ex.DayID = 0, 0
+			ex.CreatedAt, ex.UpdatedAt = time.Time{}, time.Time{}
+			dayCopy.Exercises = append(dayCopy.Exercises, ex)
+		}
+		copied.Days = append(copied.Days, dayCopy)
+	}
+
+	if err := publishWorkoutPlanTx(tx, &copied); err != nil {
+		return models.WorkoutPlan{}, nil, err
+	}
+	for i, day := range plan.Days {
+		for j, ex := range day.Exercises {
+			ids[ex.ID] = copied.Days[i].Exercises[j].ID
+		}
+	}
+	return copied, ids, nil
+}
@@ -4,6 +4,8 @@ import (
 	"encoding/json"
 	"errors"
 	"fmt"
+	"math"
+	"sort"
 	"time"
 
 	"wellnesspath/config"
@@ -15,162 +17,200 @@ import (
 
 type PlanService struct{}
 
+// maxShuffleAttempts bounds how many seeds ShufflePlan tries before giving up.
+const maxShuffleAttempts = 10
+
 // GenerateWorkoutPlan creates a personalized workout plan for the user based on their profile.
-func (s *PlanService) GenerateWorkoutPlan(userID uint64) error {
-	tx := config.DB.Begin()
+// The new plan becomes the active version; earlier versions are kept in the history.
+// Passing the seed of an earlier plan reproduces it as long as the profile and exercise catalogue are unchanged.
+// Exercises kept from the previously active plan keep their progressed loads, and its
+// active-recovery days stay active recovery where the new plan rests on the same weekday.
+func (s *PlanService) GenerateWorkoutPlan(userID uint64, options dto.GeneratePlanOptions) (dto.GeneratePlanOutput, error) {
+	if err := ensureNoSessionInProgress(userID); err != nil {
+		return dto.GeneratePlanOutput{}, err
+	}
 
-	profile, err := repositories.GetProfileByUserID(tx, userID)
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
 	if err != nil {
-		tx.Rollback()
-		return errors.New("user profile not found")
+		return dto.GeneratePlanOutput{}, errors.New("user profile not found")
 	}
 
-	var restDays []int
-	if err := json.Unmarshal([]byte(profile.RestDaysJSON), &restDays); err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to parse rest days: %w", err)
+	seed := helpers.NewPlanSeed()
+	if options.Seed != nil {
+		seed = *options.Seed
 	}
-
-	if err := helpers.ValidateSplitAndRestDays(profile.SplitType, profile.Frequency, restDays); err != nil {
-		tx.Rollback()
-		return err
+	mesocycleWeeks := helpers.DefaultMesocycleWeeks
+	if options.MesocycleWeeks != 0 {
+		mesocycleWeeks = options.MesocycleWeeks
 	}
 
-	existingPlans, err := repositories.GetAllWorkoutPlansByUserID(userID)
+	plan, err := buildPlanDraft(userID, profile, seed, mesocycleWeeks)
 	if err != nil {
-		tx.Rollback()
-		return fmt.Errorf("failed to check existing workout plans: %w", err)
+		return dto.GeneratePlanOutput{}, err
 	}
-	if len(existingPlans) > 0 {
-		if err := repositories.DeleteFullWorkoutPlanByUserIDTx(tx, userID); err != nil {
-			tx.Rollback()
-			return fmt.Errorf("failed to delete existing workout plans: %w", err)
-		}
+	if active, err := repositories.GetActiveWorkoutPlanByUserID(userID); err == nil {
+		keepActiveRecoveryDays(&plan, active)
+		keepProgressedLoads(&plan, active)
 	}
 
-	equipment := helpers.DecodeEquipment(profile.EquipmentJSON)
-	exercises, err := repositories.GetExercisesByGoalAndEquipment(profile.Goal, equipment)
-	if err != nil || len(exercises) == 0 {
-		tx.Rollback()
-		return errors.New("no exercises match your profile")
-	}
+	return publishGeneratedPlan(&plan)
+}
 
-	plan := models.WorkoutPlan{
-		UserID:    userID,
-		SplitType: profile.SplitType,
-		Goal:      profile.Goal,
-	}
-	if err := repositories.CreateWorkoutPlanTx(tx, &plan); err != nil {
-		tx.Rollback()
-		return err
+// ShufflePlan regenerates the plan with a new seed, retrying until the result differs from the active plan.
+func (s *PlanService) ShufflePlan(userID uint64) (dto.GeneratePlanOutput, error) {
+	if err := ensureNoSessionInProgress(userID); err != nil {
+		return dto.GeneratePlanOutput{}, err
 	}
 
-	splitFocuses := helpers.GetSplitFocuses(profile.SplitType, profile.Frequency)
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return dto.GeneratePlanOutput{}, errors.New("user profile not found")
+	}
 
-	restMap := map[int]bool{}
-	for _, d := range restDays {
-		restMap[d] = true
+	active, err := repositories.GetActiveWorkoutPlanByUserID(userID)
+	if err != nil {
+		return dto.GeneratePlanOutput{}, helpers.NewBadRequestError("generate a workout plan before shuffling it")
 	}
 
-	usedExerciseIDs := map[uint64]bool{}
-	focusIndex := 0
+	exMap, err := getPlanExerciseDetails(active)
+	if err != nil {
+		return dto.GeneratePlanOutput{}, fmt.Errorf("failed to retrieve exercise details: %w", err)
+	}
 
-	for dayNum := 1; dayNum <= 7; dayNum++ {
-		if restMap[dayNum] {
-			day := models.WorkoutPlanDay{
-				PlanID:    plan.ID,
-				DayNumber: dayNum,
-				Focus:     "Rest",
-			}
-			if err := repositories.CreateWorkoutPlanDayTx(tx, &day); err != nil {
-				tx.Rollback()
-				return err
-			}
-			restExercise := models.WorkoutPlanExercise{
-				DayID:      day.ID,
-				ExerciseID: 0,
-				Order:      0,
-				Reps:       0,
-				Sets:       0,
-			}
-			if err := repositories.CreateWorkoutPlanExerciseTx(tx, &restExercise); err != nil {
-				tx.Rollback()
-				return err
-			}
+	for attempt := 0; attempt < maxShuffleAttempts; attempt++ {
+		seed := helpers.NewPlanSeed()
+		if seed == active.Seed {
 			continue
 		}
 
-		if focusIndex >= len(splitFocuses) {
-			break
+		plan, err := buildPlanDraft(userID, profile, seed, mesocycleLength(active))
+		if err != nil {
+			return dto.GeneratePlanOutput{}, err
 		}
-		focus := splitFocuses[focusIndex]
-		focusIndex++
+		keepActiveRecoveryDays(&plan, active)
+		keepProgressedLoads(&plan, active)
 
-		day := models.WorkoutPlanDay{
-			PlanID:    plan.ID,
-			DayNumber: dayNum,
-			Focus:     focus,
-		}
-		if err := repositories.CreateWorkoutPlanDayTx(tx, &day); err != nil {
-			tx.Rollback()
-			return err
+		if helpers.DiffPlanDays(active.Days, plan.Days, exMap).HasChanges {
+			return publishGeneratedPlan(&plan)
 		}
+	}
 
-		focused := helpers.FilterExercisesByFocus(exercises, focus)
-		if len(focused) == 0 {
-			focused = exercises // fallback ke semua
-		}
+	return dto.GeneratePlanOutput{}, helpers.NewBadRequestError("no alternative plan is available for your profile")
+}
 
-		reps := helpers.DetermineReps(profile.Intensity, profile.Goal, profile.BMICategory)
-		exerciseCount := helpers.CalculateMaxExercises(profile.DurationPerSession, reps)
-		validParts := helpers.GetBodyPartsForFocus(focus)
+// ensureNoSessionInProgress refuses to replace the active plan, or copy it for an edit, while a
+// session logged against it is still open, since the session's sets would end up pointing at a
+// retired version.
+func ensureNoSessionInProgress(userID uint64) error {
+	if _, err := repositories.GetInProgressSessionByUserID(userID); err == nil {
+		return helpers.NewBadRequestError("finish your current session before changing your plan")
+	}
+	return nil
+}
 
-		selected := []models.Exercise{}
-		candidate := helpers.FilterWithBodyPartCoverage(focused, validParts, profile.Goal, profile.Intensity, exerciseCount)
+func publishGeneratedPlan(plan *models.WorkoutPlan) (dto.GeneratePlanOutput, error) {
+	tx := config.DB.Begin()
+	if err := publishWorkoutPlanTx(tx, plan); err != nil {
+		tx.Rollback()
+		return dto.GeneratePlanOutput{}, err
+	}
+	if err := tx.Commit().Error; err != nil {
+		return dto.GeneratePlanOutput{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
 
-		for _, ex := range candidate {
-			if !usedExerciseIDs[ex.ID] {
-				selected = append(selected, ex)
-				usedExerciseIDs[ex.ID] = true
-			}
-			if len(selected) == exerciseCount {
-				break
-			}
-		}
+	return dto.GeneratePlanOutput{
+		PlanID:  plan.ID,
+		Version: plan.Version,
+		Seed:    plan.Seed,
+	}, nil
+}
 
-		// Fallback jika belum cukup
-		if len(selected) < exerciseCount {
-			for _, ex := range focused {
-				if !usedExerciseIDs[ex.ID] {
-					selected = append(selected, ex)
-					usedExerciseIDs[ex.ID] = true
-				}
-				if len(selected) == exerciseCount {
-					break
-				}
-			}
-		}
+// PreviewRegeneration is a dry run of GenerateWorkoutPlan. The result is saved as a draft
+// and compared with the active plan; nothing changes for the user until ApplyDraft.
+// The draft reuses the active plan's seed so the diff shows the effect of the profile, not of reshuffling.
+func (s *PlanService) PreviewRegeneration(userID uint64) (dto.PlanDiff, error) {
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return dto.PlanDiff{}, errors.New("user profile not found")
+	}
 
-		if len(selected) == 0 {
-			tx.Rollback()
-			return fmt.Errorf("no suitable exercises found for focus %s", focus)
-		}
+	active, err := repositories.GetActiveWorkoutPlanByUserID(userID)
+	hasActive := err == nil
 
-		for j, ex := range selected {
-			planExercise := models.WorkoutPlanExercise{
-				DayID:      day.ID,
-				ExerciseID: ex.ID,
-				Order:      j + 1,
-				Reps:       reps,
-				Sets:       3,
-			}
-			if err := repositories.CreateWorkoutPlanExerciseTx(tx, &planExercise); err != nil {
-				tx.Rollback()
-				return err
-			}
-		}
+	seed := helpers.NewPlanSeed()
+	if hasActive {
+		seed = active.Seed
+	}
+
+	draft, err := buildPlanDraft(userID, profile, seed, mesocycleLength(active))
+	if err != nil {
+		return dto.PlanDiff{}, err
 	}
 
+	draft.IsDraft = true
+	if hasActive {
+		draft.BasePlanID = active.ID
+		keepActiveRecoveryDays(&draft, active)
+		keepProgressedLoads(&draft, active)
+	}
+
+	tx := config.DB.Begin()
+	if err := repositories.DeleteWorkoutPlanDraftsByUserIDTx(tx, userID); err != nil {
+		tx.Rollback()
+		return dto.PlanDiff{}, fmt.Errorf("failed to discard previous drafts: %w", err)
+	}
+	if err := saveWorkoutPlanTx(tx, &draft); err != nil {
+		tx.Rollback()
+		return dto.PlanDiff{}, fmt.Errorf("failed to save plan draft: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return dto.PlanDiff{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	exMap, err := getPlanExerciseDetails(models.WorkoutPlan{Days: append(append([]models.WorkoutPlanDay{}, active.Days...), draft.Days...)})
+	if err != nil {
+		return dto.PlanDiff{}, fmt.Errorf("failed to retrieve exercise details: %w", err)
+	}
+
+	diff := helpers.DiffPlanDays(active.Days, draft.Days, exMap)
+	diff.DraftPlanID = draft.ID
+	if hasActive {
+		diff.BasePlanID = active.ID
+		diff.ProfileChangedSincePlan = profile.UpdatedAt.After(active.CreatedAt)
+	}
+	return diff, nil
+}
+
+// ApplyDraft atomically replaces the active plan with a previewed draft.
+func (s *PlanService) ApplyDraft(userID uint64, draftID uint64) error {
+	draft, err := repositories.GetWorkoutPlanDraftByID(userID, draftID)
+	if err != nil {
+		return errors.New("plan draft not found")
+	}
+
+	var activeID uint64
+	if active, err := repositories.GetActiveWorkoutPlanByUserID(userID); err == nil {
+		activeID = active.ID
+	}
+	if activeID != draft.BasePlanID {
+		return helpers.NewBadRequestError("your active plan changed after this preview was made; preview again")
+	}
+
+	if err := ensureNoSessionInProgress(userID); err != nil {
+		return err
+	}
+
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return errors.New("user profile not found")
+	}
+	draft.StartDate = helpers.TodayIn(helpers.LoadUserLocation(profile.Timezone))
+
+	tx := config.DB.Begin()
+	if err := publishWorkoutPlanTx(tx, &draft); err != nil {
+		tx.Rollback()
+		return err
+	}
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -179,6 +219,10 @@ func (s *PlanService) GenerateWorkoutPlan(userID uint64) error {
 }
 
 func (s *PlanService) InitializeWorkoutPlan(userID uint64) (*dto.CreateDaysRequest, error) {
+	if err := ensureNoSessionInProgress(userID); err != nil {
+		return nil, err
+	}
+
 	tx := config.DB.Begin()
 
 	profile, err := repositories.GetProfileByUserID(tx, userID)
@@ -193,25 +237,35 @@ func (s *PlanService) InitializeWorkoutPlan(userID uint64) (*dto.CreateDaysReque
 		return nil, fmt.Errorf("failed to parse rest days: %w", err)
 	}
 
-	if err := helpers.ValidateSplitAndRestDays(profile.SplitType, profile.Frequency, restDays); err != nil {
+	split, err := resolveSplit(userID, profile.SplitType)
+	if err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
-	existingPlans, err := repositories.GetAllWorkoutPlansByUserID(userID)
+	if err := helpers.ValidateSplitAndRestDays(split, profile.Frequency, restDays); err != nil {
+		tx.Rollback()
+		return nil, err
+	}
+
+	version, err := repositories.GetNextWorkoutPlanVersionTx(tx, userID)
 	if err != nil {
 		tx.Rollback()
-		return nil, fmt.Errorf("failed to check existing workout plans: %w", err)
+		return nil, fmt.Errorf("failed to determine plan version: %w", err)
 	}
-	if len(existingPlans) > 0 {
-		if err := repositories.DeleteWorkoutPlanByUserID(tx, userID); err != nil {
-			tx.Rollback()
-			return nil, fmt.Errorf("failed to delete existing workout plans: %w", err)
-		}
+	if err := repositories.DeactivateWorkoutPlansByUserIDTx(tx, userID); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to deactivate existing workout plans: %w", err)
+	}
+
+	prefs, err := loadExercisePreferences(userID)
+	if err != nil {
+		tx.Rollback()
+		return nil, err
 	}
 
 	equipment := helpers.DecodeEquipment(profile.EquipmentJSON)
-	exercises, err := repositories.GetExercisesByGoalAndEquipment(profile.Goal, equipment)
+	exercises, err := repositories.GetExercisesByGoalAndEquipment(profile.Goal, equipment, exerciseFilterForProfile(profile, prefs))
 	if err != nil || len(exercises) == 0 {
 		tx.Rollback()
 		return nil, errors.New("no exercises match your profile")
@@ -219,14 +273,27 @@ func (s *PlanService) InitializeWorkoutPlan(userID uint64) (*dto.CreateDaysReque
 
 	plan := &models.WorkoutPlan{
 		UserID:    userID,
-		SplitType: profile.SplitType,
+		SplitType: split.Name,
 		Goal:      profile.Goal,
+		StartDate: helpers.TodayIn(helpers.LoadUserLocation(profile.Timezone)),
+		Version:   version,
+		IsActive:  true,
+		Seed:      helpers.NewPlanSeed(),
 	}
 	if err := repositories.CreateWorkoutPlanTx(tx, plan); err != nil {
 		tx.Rollback()
 		return nil, err
 	}
 
+	weeks := helpers.BuildPlanWeeks(helpers.DefaultMesocycleWeeks, profile.Intensity)
+	for i := range weeks {
+		weeks[i].PlanID = plan.ID
+	}
+	if err := repositories.CreateWorkoutPlanWeeksTx(tx, weeks); err != nil {
+		tx.Rollback()
+		return nil, fmt.Errorf("failed to save plan weeks: %w", err)
+	}
+
 	if err := tx.Commit().Error; err != nil {
 		return nil, fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -234,12 +301,25 @@ func (s *PlanService) InitializeWorkoutPlan(userID uint64) (*dto.CreateDaysReque
 	// RETURN SESUAI DTO CreateDaysRequest YANG DIBUTUHKAN FUNCTION KE-2
 	return &dto.CreateDaysRequest{
 		PlanID:   plan.ID,
+		Seed:     plan.Seed,
 		RestDays: restDays,
 		Profile:  *profile, // seluruh profil disertakan
 	}, nil
 }
 
-func (s *PlanService) CreateWorkoutPlanDays(input dto.CreateDaysRequest) (*dto.InsertExercisesRequest, error) {
+// CreateWorkoutPlanDays lays out the days of a plan started by InitializeWorkoutPlan. The plan must
+// belong to userID; the user ID inside the posted profile is ignored.
+func (s *PlanService) CreateWorkoutPlanDays(userID uint64, input dto.CreateDaysRequest) (*dto.InsertExercisesRequest, error) {
+	if _, err := repositories.GetWorkoutPlanByIDForUser(userID, input.PlanID); err != nil {
+		return nil, errors.New("workout plan not found")
+	}
+	input.Profile.UserID = userID
+
+	split, err := resolveSplit(userID, input.Profile.SplitType)
+	if err != nil {
+		return nil, err
+	}
+
 	tx := config.DB.Begin()
 
 	restMap := map[int]bool{}
@@ -247,7 +327,7 @@ func (s *PlanService) CreateWorkoutPlanDays(input dto.CreateDaysRequest) (*dto.I
 		restMap[d] = true
 	}
 
-	splitFocuses := helpers.GetSplitFocuses(input.Profile.SplitType, input.Profile.Frequency)
+	splitFocuses := split.FocusSequence(input.Profile.Frequency)
 	focusIndex := 0
 
 	var allDays []models.WorkoutPlanDay
@@ -256,13 +336,16 @@ func (s *PlanService) CreateWorkoutPlanDays(input dto.CreateDaysRequest) (*dto.I
 		day := models.WorkoutPlanDay{
 			PlanID:    input.PlanID,
 			DayNumber: dayNum,
+			DayType:   models.DayTypeRest,
 			Focus:     "Rest",
 		}
 
 		// Assign focus jika bukan hari istirahat
 		if !restMap[dayNum] {
 			if focusIndex < len(splitFocuses) {
-				day.Focus = splitFocuses[focusIndex]
+				day.DayType = models.DayTypeTraining
+				day.Focus = splitFocuses[focusIndex].Name
+				day.BodyPartsJSON = helpers.EncodeBodyParts(splitFocuses[focusIndex].BodyParts)
 				focusIndex++
 			}
 		}
@@ -273,21 +356,6 @@ func (s *PlanService) CreateWorkoutPlanDays(input dto.CreateDaysRequest) (*dto.I
 			return nil, err
 		}
 
-		// Kalau hari rest, tambahkan exercise dummy
-		if day.Focus == "Rest" {
-			restExercise := models.WorkoutPlanExercise{
-				DayID:      day.ID,
-				ExerciseID: 0,
-				Order:      0,
-				Reps:       0,
-				Sets:       0,
-			}
-			if err := repositories.CreateWorkoutPlanExerciseTx(tx, &restExercise); err != nil {
-				tx.Rollback()
-				return nil, err
-			}
-		}
-
 		allDays = append(allDays, day)
 	}
 
@@ -297,28 +365,53 @@ func (s *PlanService) CreateWorkoutPlanDays(input dto.CreateDaysRequest) (*dto.I
 
 	// Return format input untuk InsertExercisesToDays
 	return &dto.InsertExercisesRequest{
+		Seed:    input.Seed,
 		Profile: input.Profile,
 		Days:    allDays,
 	}, nil
 }
 
-func (s *PlanService) InsertExercisesToDays(input dto.InsertExercisesRequest) error {
+// InsertExercisesToDays fills the days made by CreateWorkoutPlanDays. The days are reloaded and
+// must belong to plans of userID; the user ID inside the posted profile is ignored.
+func (s *PlanService) InsertExercisesToDays(userID uint64, input dto.InsertExercisesRequest) error {
+	input.Profile.UserID = userID
+	days, err := ownedPlanDays(userID, input.Days)
+	if err != nil {
+		return err
+	}
+	input.Days = days
+
+	split, err := resolveSplit(userID, input.Profile.SplitType)
+	if err != nil {
+		return err
+	}
+
+	prefs, err := loadExercisePreferences(userID)
+	if err != nil {
+		return err
+	}
+
 	tx := config.DB.Begin()
 
 	// Ambil ulang daftar exercise dari DB berdasarkan profile
 	equipment := helpers.DecodeEquipment(input.Profile.EquipmentJSON)
-	exercises, err := repositories.GetExercisesByGoalAndEquipment(input.Profile.Goal, equipment)
+	exercises, err := repositories.GetExercisesByGoalAndEquipment(input.Profile.Goal, equipment, exerciseFilterForProfile(&input.Profile, prefs))
 	if err != nil || len(exercises) == 0 {
 		tx.Rollback()
 		return errors.New("no exercises match your profile")
 	}
 
-	splitFocuses := helpers.GetSplitFocuses(input.Profile.SplitType, input.Profile.Frequency)
+	splitFocuses := split.FocusSequence(input.Profile.Frequency)
+	rng := helpers.NewPlanRand(input.Seed)
 	usedExerciseIDs := map[uint64]bool{}
+	volume := helpers.NewVolumePlanner(input.Profile.Goal, input.Profile.Intensity)
+	exMap := exercisesByID(exercises)
+	limitations := helpers.DecodeLimitations(input.Profile.LimitationsJSON)
 	focusIndex := 0
 
+	var trainingDays []models.WorkoutPlanDay
 	for _, day := range input.Days {
-		if day.Focus == "Rest" {
+		if models.IsRestDay(day) {
 			continue
 		}
 
@@ -328,58 +421,34 @@ func (s *PlanService) InsertExercisesToDays(input dto.InsertExercisesRequest) er
 		focus := splitFocuses[focusIndex]
 		focusIndex++
 
-		reps := helpers.DetermineReps(input.Profile.Intensity, input.Profile.Goal, input.Profile.BMICategory)
-		exerciseCount := helpers.CalculateMaxExercises(input.Profile.DurationPerSession, reps)
-		validParts := helpers.GetBodyPartsForFocus(focus)
-
-		focused := helpers.FilterExercisesByFocus(exercises, focus)
-		if len(focused) == 0 {
-			focused = exercises
+		batch, err := selectExercisesForFocus(rng, exercises, exMap, limitations, &input.Profile, focus, usedExerciseIDs, volume, prefs.favourites)
+		if err != nil {
+			tx.Rollback()
+			return fmt.Errorf("no suitable exercises found for day %d", day.DayNumber)
 		}
-
-		candidate := helpers.FilterWithBodyPartCoverage(focused, validParts, input.Profile.Goal, input.Profile.Intensity, exerciseCount)
-
-		selected := []models.Exercise{}
-		for _, ex := range candidate {
-			if !usedExerciseIDs[ex.ID] {
-				selected = append(selected, ex)
-				usedExerciseIDs[ex.ID] = true
-			}
-			if len(selected) == exerciseCount {
-				break
-			}
+		for i := range batch {
+			batch[i].DayID = day.ID
 		}
+		day.Exercises = batch
+		trainingDays = append(trainingDays, day)
+	}
 
-		if len(selected) < exerciseCount {
-			for _, ex := range focused {
-				if !usedExerciseIDs[ex.ID] {
-					selected = append(selected, ex)
-					usedExerciseIDs[ex.ID] = true
-				}
-				if len(selected) == exerciseCount {
-					break
-				}
-			}
-		}
+	// Set per minggu diseimbangkan setelah semua hari terisi
+	helpers.BalanceWeeklySets(trainingDays, exMap, volume.Target)
 
-		if len(selected) == 0 {
-			tx.Rollback()
-			return fmt.Errorf("no suitable exercises found for day %d", day.DayNumber)
+	for _, day := range trainingDays {
+		helpers.FitDayToDuration(&day, exMap, input.Profile.Goal, input.Profile.DurationPerSession, limitations)
+		helpers.GroupDayExercises(&day, exMap, input.Profile.Goal)
+		for i := range day.Groups {
+			day.Groups[i].DayID = day.ID
 		}
-
-		// ✅ Batch Insert for the current day
-		batch := make([]models.WorkoutPlanExercise, 0, len(selected))
-		for i, ex := range selected {
-			batch = append(batch, models.WorkoutPlanExercise{
-				DayID:      day.ID,
-				ExerciseID: ex.ID,
-				Order:      i + 1,
-				Reps:       reps,
-				Sets:       3,
-			})
+		if len(day.Groups) > 0 {
+			if err := repositories.CreateWorkoutPlanGroupsTx(tx, day.Groups); err != nil {
+				tx.Rollback()
+				return fmt.Errorf("failed to save exercise groups for day %d: %w", day.DayNumber, err)
+			}
 		}
-
-		if err := repositories.CreateWorkoutPlanExercisesBatchTx(tx, batch); err != nil {
+		if err := repositories.CreateWorkoutPlanExercisesBatchTx(tx, day.Exercises); err != nil {
 			tx.Rollback()
 			return fmt.Errorf("batch insert failed for day %d: %w", day.DayNumber, err)
 		}
@@ -392,11 +461,42 @@ func (s *PlanService) InsertExercisesToDays(input dto.InsertExercisesRequest) er
 	return nil
 }
 
+// ownedPlanDays reloads posted plan days, in the posted order, and checks that each belongs to a plan of userID.
+func ownedPlanDays(userID uint64, posted []models.WorkoutPlanDay) ([]models.WorkoutPlanDay, error) {
+	ids := make([]uint64, 0, len(posted))
+	for _, day := range posted {
+		ids = append(ids, day.ID)
+	}
+	dayMap, err := repositories.GetWorkoutPlanDaysByIDs(ids)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve workout days: %w", err)
+	}
+
+	owned := map[uint64]bool{}
+	days := make([]models.WorkoutPlanDay, 0, len(posted))
+	for _, day := range posted {
+		stored, ok := dayMap[day.ID]
+		if !ok {
+			return nil, errors.New("workout day not found")
+		}
+		if _, checked := owned[stored.PlanID]; !checked {
+			_, err := repositories.GetWorkoutPlanByIDForUser(userID, stored.PlanID)
+			owned[stored.PlanID] = err == nil
+		}
+		if !owned[stored.PlanID] {
+			return nil, errors.New("workout day not found")
+		}
+		days = append(days, stored)
+	}
+	return days, nil
+}
+
 func (s *PlanService) GetAllPlans(userID uint64) ([]models.WorkoutPlan, error) {
 	return repositories.GetAllWorkoutPlansByUserID(userID)
 }
 
-func (s *PlanService) GetPlanByUserID(userID uint64) (dto.FullPlanOutput, error) {
+// GetPlanByUserID returns the active plan together with a dated schedule covering weeks weeks from its start date.
+func (s *PlanService) GetPlanByUserID(userID uint64, weeks int) (dto.FullPlanOutput, error) {
 	plan, err := repositories.GetActiveWorkoutPlanByUserID(userID)
 	if err != nil {
 		return dto.FullPlanOutput{}, fmt.Errorf("failed to retrieve workout plan: %w", err)
@@ -407,77 +507,249 @@ func (s *PlanService) GetPlanByUserID(userID uint64) (dto.FullPlanOutput, error)
 		return dto.FullPlanOutput{}, fmt.Errorf("failed to retrieve profile: %w", err)
 	}
 
-	// Kumpulkan semua ExerciseID
-	uniqueIDs := make(map[uint64]struct{})
-	for _, day := range plan.Days {
-		for _, ex := range day.Exercises {
-			if ex.ExerciseID != 0 {
-				uniqueIDs[ex.ExerciseID] = struct{}{}
-			}
-		}
+	return buildFullPlanOutput(plan, profile, weeks)
+}
+
+// GetPlanByID returns any version of the user's plan, active or not.
+func (s *PlanService) GetPlanByID(userID uint64, planID uint64, weeks int) (dto.FullPlanOutput, error) {
+	plan, err := repositories.GetWorkoutPlanByIDForUser(userID, planID)
+	if err != nil {
+		return dto.FullPlanOutput{}, errors.New("workout plan not found")
 	}
 
-	var ids []uint64
-	for id := range uniqueIDs {
-		ids = append(ids, id)
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return dto.FullPlanOutput{}, fmt.Errorf("failed to retrieve profile: %w", err)
+	}
+
+	return buildFullPlanOutput(plan, profile, weeks)
+}
+
+// GetPlanHistory lists every stored plan version, newest first, with the number of sessions logged against each.
+func (s *PlanService) GetPlanHistory(userID uint64) ([]dto.PlanVersionSummary, error) {
+	plans, err := repositories.GetWorkoutPlanVersionsByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve plan history: %w", err)
+	}
+
+	sessionCounts, err := repositories.CountSessionsByPlanID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to count sessions: %w", err)
+	}
+
+	history := make([]dto.PlanVersionSummary, 0, len(plans))
+	for _, plan := range plans {
+		history = append(history, dto.PlanVersionSummary{
+			PlanID:       plan.ID,
+			Version:      plan.Version,
+			Seed:         plan.Seed,
+			SplitType:    plan.SplitType,
+			Goal:         plan.Goal,
+			StartDate:    helpers.PlanStartDate(plan).Format(helpers.DateLayout),
+			IsActive:     plan.IsActive,
+			SessionCount: sessionCounts[plan.ID],
+			CreatedAt:    plan.CreatedAt,
+		})
+	}
+	return history, nil
+}
+
+// ActivatePlan makes an earlier version the active plan again. The version is restored as it was
+// last edited (see models.WorkoutPlan for when edits copy a version) and restarts today, like
+// an applied draft, so its day numbers line up with the calendar again.
+func (s *PlanService) ActivatePlan(userID uint64, planID uint64) error {
+	plan, err := repositories.GetWorkoutPlanByIDForUser(userID, planID)
+	if err != nil {
+		return errors.New("workout plan not found")
+	}
+	if plan.IsActive {
+		return nil
+	}
+
+	if err := ensureNoSessionInProgress(userID); err != nil {
+		return err
+	}
+
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return errors.New("user profile not found")
+	}
+
+	tx := config.DB.Begin()
+	if err := repositories.DeactivateWorkoutPlansByUserIDTx(tx, userID); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to deactivate existing workout plans: %w", err)
+	}
+	if err := repositories.ActivateWorkoutPlanTx(tx, plan.ID, helpers.TodayIn(helpers.LoadUserLocation(profile.Timezone))); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to activate workout plan: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
 
-	exDetails, err := repositories.GetExercisesByIDs(ids)
+	return nil
+}
+
+func buildFullPlanOutput(plan models.WorkoutPlan, profile *models.Profile, weeks int) (dto.FullPlanOutput, error) {
+	exMap, err := getPlanExerciseDetails(plan)
 	if err != nil {
 		return dto.FullPlanOutput{}, err
 	}
 
-	exMap := make(map[uint64]*models.Exercise)
-	for _, e := range exDetails {
-		exMap[e.ID] = e
+	today := helpers.TodayIn(helpers.LoadUserLocation(profile.Timezone))
+	currentWeek := helpers.PlanWeekDTOForDate(plan, today)
+	phase := ""
+	if currentWeek != nil {
+		phase = currentWeek.Phase
 	}
+	limitations := helpers.DecodeLimitations(profile.LimitationsJSON)
 
 	var workoutDays []dto.WorkoutDay
 	for _, day := range plan.Days {
 		var dayDTO dto.WorkoutDay
+		dayDTO.DayID = day.ID
 		dayDTO.DayNumber = day.DayNumber
+		dayDTO.Weekday = helpers.WeekdayName(day.DayNumber)
+		dayDTO.DayType = day.DayType
 		dayDTO.Focus = day.Focus
+		dayDTO.Exercises = []dto.ExercisePlanResponse{}
+		if !models.IsRestDay(day) {
+			dayDTO.BodyParts = helpers.DayBodyParts(day)
+		}
 
+		var adjustedExercises []models.WorkoutPlanExercise
 		for _, ex := range day.Exercises {
-			if ex.ExerciseID == 0 {
-				dayDTO.Exercises = append(dayDTO.Exercises, dto.ExercisePlanResponse{
-					ExerciseID: 0,
-					Name:       "Rest Day",
-					Reps:       ex.Reps,
-					Sets:       ex.Sets,
-					Order:      ex.Order,
-					BodyPart:   "-",
-					Equipment:  "-",
-				})
+			detail, ok := exMap[ex.ExerciseID]
+			if !ok {
 				continue
 			}
-
-			detail := exMap[ex.ExerciseID]
+			adjusted := helpers.AdjustExercise(ex, phase)
+			adjustedExercises = append(adjustedExercises, adjusted)
 			dayDTO.Exercises = append(dayDTO.Exercises, dto.ExercisePlanResponse{
-				ExerciseID: ex.ExerciseID,
-				Name:       detail.Name,
-				Reps:       ex.Reps,
-				Sets:       ex.Sets,
-				Order:      ex.Order,
-				BodyPart:   detail.BodyPart,
-				Equipment:  detail.Equipment,
+				PlanExerciseID:  ex.ID,
+				ExerciseID:      ex.ExerciseID,
+				Name:            detail.Name,
+				Block:           helpers.BlockMain,
+				Mode:            adjusted.Mode,
+				Reps:            adjusted.Reps,
+				Sets:            adjusted.Sets,
+				Load:            adjusted.Load,
+				RestSeconds:     ex.RestSeconds,
+				Tempo:           ex.Tempo,
+				TargetRPE:       ex.TargetRPE,
+				TargetRIR:       ex.TargetRIR,
+				DurationSeconds: adjusted.DurationSeconds,
+				DistanceMeters:  adjusted.DistanceMeters,
+				TargetPace:      ex.TargetPace,
+				HeartRateZone:   ex.HeartRateZone,
+				WorkSeconds:     ex.WorkSeconds,
+				Order:           ex.Order,
+				GroupNumber:     ex.GroupNumber,
+				Base:            helpers.BasePrescription(ex, phase),
+				BodyPart:        detail.BodyPart,
+				Equipment:       detail.Equipment,
+				SelectionReason: helpers.DecodeSelectionReason(ex.SelectionReasonJSON),
 			})
 		}
+		dayDTO.Groups = helpers.BuildExerciseGroups(day.Groups, adjustedExercises, phase)
+		dayDTO.Warmup = helpers.BuildWarmup(dayDTO.BodyParts, adjustedExercises, exMap, limitations)
+		dayDTO.Cooldown = helpers.BuildCooldown(dayDTO.BodyParts, adjustedExercises, limitations)
+		dayDTO.EstimatedDurationMinutes = helpers.EstimateSessionMinutes(append(dayDTO.Warmup, dayDTO.Cooldown...), adjustedExercises, day.Groups, phase)
+		if models.IsRestDay(day) {
+			dayDTO.Recovery = helpers.BuildActiveRecovery(helpers.PreviousTrainingBodyParts(plan.Days, day.DayNumber), day.DayType == models.DayTypeRest, limitations)
+		}
 		workoutDays = append(workoutDays, dayDTO)
 	}
 
+	startDate := helpers.PlanStartDate(plan)
+	endDate := startDate.AddDate(0, 0, weeks*7-1)
+
 	return dto.FullPlanOutput{
+		PlanID:         plan.ID,
+		Version:        plan.Version,
+		Seed:           plan.Seed,
+		IsActive:       plan.IsActive,
+		StartDate:      startDate.Format(helpers.DateLayout),
+		Mesocycle:      helpers.BuildPlanWeekDTOs(plan.Weeks),
+		CurrentWeek:    currentWeek,
 		WorkoutPlan:    workoutDays,
+		Schedule:       helpers.BuildSchedule(plan, exMap, startDate, endDate),
+		WeeklyVolume:   helpers.SummarizeWeeklyVolume(plan.Days, exMap, helpers.WeeklyVolumeTarget(plan.Goal, profile.Intensity)),
 		TrainingAdvice: helpers.GenerateTrainingAdvice(profile),
 		BMIInfo:        helpers.BuildBMIInfo(profile.BMI, profile.BMICategory),
-		CaloriesBurned: helpers.CalculateCalories(profile),
+		CaloriesBurned: helpers.CalculateCalories(profile, plan.Days, exMap, weeks),
 		NutritionPlan:  helpers.GenerateNutrition(profile),
 	}, nil
 }
 
+// GetSchedule returns the dated exercises of the active plan between from and to (inclusive).
+// An empty from defaults to today in the user's timezone, an empty to to DefaultScheduleWeeks weeks later.
+func (s *PlanService) GetSchedule(userID uint64, from string, to string) (dto.ScheduleOutput, error) {
+	plan, err := repositories.GetActiveWorkoutPlanByUserID(userID)
+	if err != nil {
+		return dto.ScheduleOutput{}, fmt.Errorf("user has no active workout plan")
+	}
+
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return dto.ScheduleOutput{}, fmt.Errorf("failed to retrieve profile: %w", err)
+	}
+
+	fromDate := helpers.TodayIn(helpers.LoadUserLocation(profile.Timezone))
+	if from != "" {
+		if fromDate, err = helpers.ParseCivilDate(from); err != nil {
+			return dto.ScheduleOutput{}, helpers.NewBadRequestError("from must use the YYYY-MM-DD format")
+		}
+	}
+
+	toDate := fromDate.AddDate(0, 0, helpers.DefaultScheduleWeeks*7-1)
+	if to != "" {
+		if toDate, err = helpers.ParseCivilDate(to); err != nil {
+			return dto.ScheduleOutput{}, helpers.NewBadRequestError("to must use the YYYY-MM-DD format")
+		}
+	}
+
+	if toDate.Before(fromDate) {
+		return dto.ScheduleOutput{}, helpers.NewBadRequestError("to must not be before from")
+	}
+	if toDate.Sub(fromDate) >= helpers.MaxScheduleRangeDays*24*time.Hour {
+		return dto.ScheduleOutput{}, helpers.NewBadRequestError(
+			fmt.Sprintf("date range cannot exceed %d days", helpers.MaxScheduleRangeDays))
+	}
+
+	exMap, err := getPlanExerciseDetails(plan)
+	if err != nil {
+		return dto.ScheduleOutput{}, err
+	}
+
+	return dto.ScheduleOutput{
+		From:     fromDate.Format(helpers.DateLayout),
+		To:       toDate.Format(helpers.DateLayout),
+		Schedule: helpers.BuildSchedule(plan, exMap, fromDate, toDate),
+	}, nil
+}
+
+// getPlanExerciseDetails loads the exercise rows referenced by a plan in one query.
+func getPlanExerciseDetails(plan models.WorkoutPlan) (map[uint64]*models.Exercise, error) {
+	uniqueIDs := make(map[uint64]struct{})
+	for _, day := range plan.Days {
+		for _, ex := range day.Exercises {
+			uniqueIDs[ex.ExerciseID] = struct{}{}
+		}
+	}
+
+	var ids []uint64
+	for id := range uniqueIDs {
+		ids = append(ids, id)
+	}
+
+	return repositories.GetExercisesByIDs(ids)
+}
+
 func (s *PlanService) DeletePlan(userID uint64) error {
 	tx := config.DB.Begin()
-	err := repositories.DeleteWorkoutPlanByUserID(tx, userID)
+	err := repositories.DeleteActiveWorkoutPlanByUserID(tx, userID)
 	if err != nil {
 		tx.Rollback()
 		return err
@@ -532,10 +804,29 @@ func (s *PlanService) GetRecommendedReplacements(userID uint64) ([]dto.ExerciseR
 	}
 
 	// 4. Ambil kandidat replacement dengan batch query di repositories
-	candidateExercises, err := repositories.FindExercisesByBodyPartsAndEquipment(config.DB, uniqueBodyParts, equipment, allExerciseIDs)
+	prefs, err := loadExercisePreferences(userID)
+	if err != nil {
+		return nil, err
+	}
+	candidateExercises, err := repositories.FindExercisesByBodyPartsAndEquipment(config.DB, uniqueBodyParts, equipment, allExerciseIDs, exerciseFilterForProfile(profile, prefs))
 	if err != nil {
 		return nil, err
 	}
+	// Favourites are suggested first, exercises the user has to limit last
+	limitations := helpers.DecodeLimitations(profile.LimitationsJSON)
+	replacementRank := func(ex models.Exercise) int {
+		switch {
+		case prefs.favourites[ex.ID]:
+			return 0
+		case helpers.LimitationSeverity(ex, limitations) != "":
+			return 2
+		default:
+			return 1
+		}
+	}
+	sort.SliceStable(candidateExercises, func(i, j int) bool {
+		return replacementRank(candidateExercises[i]) < replacementRank(candidateExercises[j])
+	})
 	// Group candidate by bodypart
 	candidatesByBodyPart := map[string][]models.Exercise{}
 	for _, c := range candidateExercises {
@@ -593,11 +884,8 @@ func (s *PlanService) GetRecommendedReplacements(userID uint64) ([]dto.ExerciseR
 }
 
 func (s *PlanService) ReplaceExercise(userID uint64, req dto.ReplaceExerciseRequest) error {
-	tx := config.DB.Begin()
-
 	plan, err := repositories.GetActiveWorkoutPlanByUserID(userID)
 	if err != nil {
-		tx.Rollback()
 		return fmt.Errorf("user has no active workout plan")
 	}
 
@@ -616,14 +904,55 @@ func (s *PlanService) ReplaceExercise(userID uint64, req dto.ReplaceExerciseRequ
 		}
 	}
 	if !found {
-		tx.Rollback()
 		return fmt.Errorf("original exercise not found in your plan")
 	}
-	err = repositories.UpdateExerciseInPlanExercise(tx, targetPlanExerciseID, req.NewExerciseID)
+
+	newExercise, err := repositories.GetExerciseByID(req.NewExerciseID)
+	if err != nil {
+		return helpers.NewBadRequestError("replacement exercise not found")
+	}
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return errors.New("user profile not found")
+	}
+	prefs, err := loadExercisePreferences(userID)
+	if err != nil {
+		return err
+	}
+	if prefs.blocked[newExercise.ID] {
+		return helpers.NewBadRequestError(fmt.Sprintf("%s is on your blocked list", newExercise.Name))
+	}
+	if helpers.LimitationSeverity(*newExercise, helpers.DecodeLimitations(profile.LimitationsJSON)) == helpers.LimitationSeverityAvoid {
+		return helpers.NewBadRequestError(fmt.Sprintf("%s conflicts with a limitation on your profile", newExercise.Name))
+	}
+
+	tx := config.DB.Begin()
+	_, planExerciseIDs, err := editableActivePlanTx(tx, plan)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	targetPlanExerciseID = planExerciseIDs[targetPlanExerciseID]
+
+	err = repositories.UpdateExerciseInPlanExercise(tx, targetPlanExerciseID, newExercise.ID)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to update exercise: %w", err)
 	}
+
+	// Blocking the original keeps it out of future plans and replacement suggestions
+	if req.BlockOriginal {
+		if err := repositories.SetExercisePreferenceTx(tx, userID, req.OriginalExerciseID, models.ExercisePreferenceBlocked); err != nil {
+			tx.Rollback()
+			return fmt.Errorf("failed to block exercise: %w", err)
+		}
+	}
+
+	reason := helpers.ReplacementSelectionReason(*newExercise, profile)
+	if err := repositories.UpdatePlanExerciseSelectionReason(tx, targetPlanExerciseID, helpers.EncodeSelectionReason(reason)); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update exercise: %w", err)
+	}
 	if err := tx.Commit().Error; err != nil {
 		return fmt.Errorf("failed to commit transaction: %w", err)
 	}
@@ -658,7 +987,12 @@ func (s *PlanService) EditReps(userID uint64, input dto.EditRepsRequest) error {
 		tx.Rollback()
 		return fmt.Errorf("exercise not found in your plan")
 	}
-	err = repositories.UpdateWorkoutPlanExerciseReps(tx, targetPlanExerciseID, input.NewReps)
+	_, planExerciseIDs, err := editableActivePlanTx(tx, plan)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	err = repositories.UpdateWorkoutPlanExerciseReps(tx, planExerciseIDs[targetPlanExerciseID], input.NewReps)
 	if err != nil {
 		tx.Rollback()
 		return fmt.Errorf("failed to update reps for exercise: %w", err)
@@ -670,24 +1004,191 @@ func (s *PlanService) EditReps(userID uint64, input dto.EditRepsRequest) error {
 	return nil
 }
 
-func (s *PlanService) GetWorkoutToday(userID uint64, dayID uint64) (dto.FullDayPlanOutput, error) {
+// EditTargets changes the rest, tempo and effort targets of one exercise in the active plan.
+// When only one of RPE and RIR is given the other is derived from it.
+func (s *PlanService) EditTargets(userID uint64, input dto.EditTargetsRequest) error {
+	if input.RestSeconds == nil && input.Tempo == nil && input.TargetRPE == nil && input.TargetRIR == nil {
+		return helpers.NewBadRequestError("provide at least one of restSeconds, tempo, targetRpe or targetRir")
+	}
+
+	plan, err := repositories.GetActiveWorkoutPlanByUserID(userID)
+	if err != nil {
+		return fmt.Errorf("user has no active workout plan")
+	}
+
+	var target *models.WorkoutPlanExercise
+	for _, day := range plan.Days {
+		for i := range day.Exercises {
+			if day.Exercises[i].ID == input.PlanExerciseID {
+				target = &day.Exercises[i]
+			}
+		}
+	}
+	if target == nil {
+		return fmt.Errorf("exercise not found in your plan")
+	}
+
+	targets := helpers.TrainingTargetsOf(*target)
+	if input.RestSeconds != nil {
+		targets.RestSeconds = *input.RestSeconds
+	}
+	if input.Tempo != nil {
+		tempo, err := helpers.NormalizeTempo(*input.Tempo)
+		if err != nil {
+			return helpers.NewBadRequestError(err.Error())
+		}
+		targets.Tempo = tempo
+	}
+	switch {
+	case input.TargetRPE != nil && input.TargetRIR != nil:
+		if math.Abs(helpers.RPEForRIR(*input.TargetRIR)-*input.TargetRPE) > 1 {
+			return helpers.NewBadRequestError("targetRpe and targetRir disagree; RPE is roughly 10 minus reps in reserve")
+		}
+		targets.TargetRPE, targets.TargetRIR = *input.TargetRPE, *input.TargetRIR
+	case input.TargetRPE != nil:
+		targets.TargetRPE, targets.TargetRIR = *input.TargetRPE, helpers.RIRForRPE(*input.TargetRPE)
+	case input.TargetRIR != nil:
+		targets.TargetRPE, targets.TargetRIR = helpers.RPEForRIR(*input.TargetRIR), *input.TargetRIR
+	}
+
 	tx := config.DB.Begin()
+	_, planExerciseIDs, err := editableActivePlanTx(tx, plan)
+	if err != nil {
+		tx.Rollback()
+		return err
+	}
+	if err := repositories.UpdateWorkoutPlanExerciseTargets(tx, planExerciseIDs[target.ID], targets.RestSeconds, targets.Tempo, targets.TargetRPE, targets.TargetRIR); err != nil {
+		tx.Rollback()
+		return fmt.Errorf("failed to update exercise targets: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return nil
+}
+
+// SetRestDayType switches a rest day of the active plan between plain rest and active recovery
+// and returns the updated day.
+func (s *PlanService) SetRestDayType(userID uint64, dayNumber int, dayType string) (dto.FullDayPlanOutput, error) {
+	if !helpers.IsValidDayNumber(dayNumber) {
+		return dto.FullDayPlanOutput{}, helpers.NewBadRequestError("day number must be between 1 (Monday) and 7 (Sunday)")
+	}
 
 	plan, err := repositories.GetActiveWorkoutPlanByUserID(userID)
 	if err != nil {
 		return dto.FullDayPlanOutput{}, fmt.Errorf("user has no active workout plan")
 	}
 
-	var day models.WorkoutPlanDay
-	if err := tx.Where("plan_id = ? AND day_number = ?", plan.ID, dayID).First(&day).Error; err != nil {
-		return dto.FullDayPlanOutput{}, fmt.Errorf("workout plan day not found")
+	day := planDayByNumber(plan, dayNumber)
+	if day == nil || !models.IsRestDay(*day) {
+		return dto.FullDayPlanOutput{}, helpers.NewBadRequestError("only rest days can be switched between rest and active recovery")
+	}
+
+	tx := config.DB.Begin()
+	plan, _, err = editableActivePlanTx(tx, plan)
+	if err != nil {
+		tx.Rollback()
+		return dto.FullDayPlanOutput{}, err
+	}
+	day = planDayByNumber(plan, dayNumber)
+	if err := repositories.UpdateWorkoutPlanDayType(tx, day.ID, dayType); err != nil {
+		tx.Rollback()
+		return dto.FullDayPlanOutput{}, fmt.Errorf("failed to update day type: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return dto.FullDayPlanOutput{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+	day.DayType = dayType
+
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return dto.FullDayPlanOutput{}, fmt.Errorf("failed to retrieve profile: %w", err)
+	}
+
+	today := helpers.TodayIn(helpers.LoadUserLocation(profile.Timezone))
+	return buildDayPlanOutput(plan, profile, dayNumber, today, "")
+}
+
+func planDayByNumber(plan models.WorkoutPlan, dayNumber int) *models.WorkoutPlanDay {
+	for i := range plan.Days {
+		if plan.Days[i].DayNumber == dayNumber {
+			return &plan.Days[i]
+		}
+	}
+	return nil
+}
+
+// GetWorkoutToday resolves the current day in the user's timezone and returns its workout.
+func (s *PlanService) GetWorkoutToday(userID uint64) (dto.FullDayPlanOutput, error) {
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return dto.FullDayPlanOutput{}, fmt.Errorf("failed to retrieve profile: %w", err)
+	}
+
+	today := helpers.TodayIn(helpers.LoadUserLocation(profile.Timezone))
+	return s.getWorkoutForDate(userID, profile, today)
+}
+
+// GetWorkoutByDate returns the workout scheduled on a YYYY-MM-DD date.
+func (s *PlanService) GetWorkoutByDate(userID uint64, date string) (dto.FullDayPlanOutput, error) {
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return dto.FullDayPlanOutput{}, fmt.Errorf("failed to retrieve profile: %w", err)
+	}
+
+	parsed, err := helpers.ParseCivilDate(date)
+	if err != nil {
+		return dto.FullDayPlanOutput{}, helpers.NewBadRequestError("date must use the YYYY-MM-DD format")
+	}
+	return s.getWorkoutForDate(userID, profile, parsed)
+}
+
+// GetWorkoutByDayNumber returns the workout for a weekday number (1 = Monday ... 7 = Sunday).
+func (s *PlanService) GetWorkoutByDayNumber(userID uint64, dayNumber int) (dto.FullDayPlanOutput, error) {
+	if !helpers.IsValidDayNumber(dayNumber) {
+		return dto.FullDayPlanOutput{}, helpers.NewBadRequestError("day number must be between 1 (Monday) and 7 (Sunday)")
+	}
+
+	plan, err := repositories.GetActiveWorkoutPlanByUserID(userID)
+	if err != nil {
+		return dto.FullDayPlanOutput{}, fmt.Errorf("user has no active workout plan")
 	}
 
-	var exercises []models.WorkoutPlanExercise
-	if err := tx.Where("day_id = ?", day.ID).Find(&exercises).Error; err != nil {
-		return dto.FullDayPlanOutput{}, fmt.Errorf("failed to fetch exercises for the day")
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return dto.FullDayPlanOutput{}, fmt.Errorf("failed to retrieve profile: %w", err)
 	}
 
+	today := helpers.TodayIn(helpers.LoadUserLocation(profile.Timezone))
+	return buildDayPlanOutput(plan, profile, dayNumber, today, "")
+}
+
+func (s *PlanService) getWorkoutForDate(userID uint64, profile *models.Profile, date time.Time) (dto.FullDayPlanOutput, error) {
+	plan, err := repositories.GetActiveWorkoutPlanByUserID(userID)
+	if err != nil {
+		return dto.FullDayPlanOutput{}, fmt.Errorf("user has no active workout plan")
+	}
+
+	startDate := helpers.PlanStartDate(plan)
+	if date.Before(startDate) {
+		return dto.FullDayPlanOutput{}, helpers.NewBadRequestError(
+			fmt.Sprintf("your workout plan starts on %s", startDate.Format(helpers.DateLayout)))
+	}
+
+	return buildDayPlanOutput(plan, profile, helpers.DayNumberForDate(date), date, date.Format(helpers.DateLayout))
+}
+
+// buildDayPlanOutput returns one plan day with its prescription adjusted for the mesocycle week of weekOf.
+func buildDayPlanOutput(plan models.WorkoutPlan, profile *models.Profile, dayNumber int, weekOf time.Time, date string) (dto.FullDayPlanOutput, error) {
+	day := planDayByNumber(plan, dayNumber)
+	if day == nil {
+		return dto.FullDayPlanOutput{}, fmt.Errorf("workout plan day not found")
+	}
+
+	exercises := day.Exercises
+	sort.Slice(exercises, func(i, j int) bool { return exercises[i].Order < exercises[j].Order })
+
 	exerciseIDs := make([]uint64, 0, len(exercises))
 	for _, ex := range exercises {
 		exerciseIDs = append(exerciseIDs, ex.ExerciseID)
@@ -698,11 +1199,22 @@ func (s *PlanService) GetWorkoutToday(userID uint64, dayID uint64) (dto.FullDayP
 		return dto.FullDayPlanOutput{}, fmt.Errorf("failed to retrieve exercise details: %w", err)
 	}
 
-	var workoutDayOutput dto.WorkoutDayToday
-	workoutDayOutput.DayNumber = day.DayNumber
-	workoutDayOutput.Focus = day.Focus
+	workoutDayOutput := dto.WorkoutDayToday{
+		DayID:     day.ID,
+		DayNumber: day.DayNumber,
+		Weekday:   helpers.WeekdayName(day.DayNumber),
+		Date:      date,
+		DayType:   day.DayType,
+		Focus:     day.Focus,
+		Exercises: []dto.ExerciseTodayResponse{},
+		Week:      helpers.PlanWeekDTOForDate(plan, weekOf),
+	}
+	phase := ""
+	if workoutDayOutput.Week != nil {
+		phase = workoutDayOutput.Week.Phase
+	}
 
-	var allGoalTags []string
+	var adjustedExercises []models.WorkoutPlanExercise
 	for _, ex := range exercises {
 		detail, ok := exMap[ex.ExerciseID]
 		if !ok {
@@ -715,31 +1227,44 @@ func (s *PlanService) GetWorkoutToday(userID uint64, dayID uint64) (dto.FullDayP
 			imageURL = ""
 		}
 
+		adjusted := helpers.AdjustExercise(ex, phase)
+		adjustedExercises = append(adjustedExercises, adjusted)
 		workoutDayOutput.Exercises = append(workoutDayOutput.Exercises, dto.ExerciseTodayResponse{
-			ExerciseID: ex.ExerciseID,
-			Name:       detail.Name,
-			Reps:       ex.Reps,
-			Sets:       ex.Sets,
-			Order:      ex.Order,
-			ImageURL:   imageURL,
+			PlanExerciseID:  ex.ID,
+			ExerciseID:      ex.ExerciseID,
+			Name:            detail.Name,
+			Block:           helpers.BlockMain,
+			Mode:            adjusted.Mode,
+			Reps:            adjusted.Reps,
+			Sets:            adjusted.Sets,
+			Load:            adjusted.Load,
+			RestSeconds:     ex.RestSeconds,
+			Tempo:           ex.Tempo,
+			TargetRPE:       ex.TargetRPE,
+			TargetRIR:       ex.TargetRIR,
+			DurationSeconds: adjusted.DurationSeconds,
+			DistanceMeters:  adjusted.DistanceMeters,
+			TargetPace:      ex.TargetPace,
+			HeartRateZone:   ex.HeartRateZone,
+			WorkSeconds:     ex.WorkSeconds,
+			Order:           ex.Order,
+			GroupNumber:     ex.GroupNumber,
+			Base:            helpers.BasePrescription(ex, phase),
+			ImageURL:        imageURL,
 		})
-
-		allGoalTags = append(allGoalTags, detail.GoalTag)
 	}
-
-	profile, err := repositories.GetProfileByUserID(tx, userID)
-	if err != nil {
-		return dto.FullDayPlanOutput{}, fmt.Errorf("failed to retrieve profile: %w", err)
+	workoutDayOutput.Groups = helpers.BuildExerciseGroups(day.Groups, adjustedExercises, phase)
+	bodyParts := helpers.DayBodyParts(*day)
+	limitations := helpers.DecodeLimitations(profile.LimitationsJSON)
+	workoutDayOutput.Warmup = helpers.BuildWarmup(bodyParts, adjustedExercises, exMap, limitations)
+	workoutDayOutput.Cooldown = helpers.BuildCooldown(bodyParts, adjustedExercises, limitations)
+	workoutDayOutput.EstimatedDurationMinutes = helpers.EstimateSessionMinutes(append(workoutDayOutput.Warmup, workoutDayOutput.Cooldown...), adjustedExercises, day.Groups, phase)
+	if models.IsRestDay(*day) {
+		workoutDayOutput.Recovery = helpers.BuildActiveRecovery(helpers.PreviousTrainingBodyParts(plan.Days, day.DayNumber), day.DayType == models.DayTypeRest, limitations)
 	}
 
-	output := dto.FullDayPlanOutput{
+	return dto.FullDayPlanOutput{
 		WorkoutDay:     workoutDayOutput,
-		CaloriesBurned: helpers.CalculateTodayCalories(allGoalTags, profile.TargetWeight),
-	}
-
-	if err := tx.Commit().Error; err != nil {
-		return dto.FullDayPlanOutput{}, fmt.Errorf("failed to commit transaction: %w", err)
-	}
-
-	return output, nil
+		CaloriesBurned: helpers.CalculateTodayCalories(adjustedExercises, exMap, profile.TargetWeight),
+	}, nil
 }
@@ -3,6 +3,8 @@ package services
 import (
 	"encoding/json"
 	"errors"
+	"fmt"
+	"sort"
 	"strings"
 	"wellnesspath/config"
 	"wellnesspath/dto"
@@ -27,6 +29,11 @@ func (s *ProfileService) GetProfile(userID uint64) (dto.ProfileResponseDTO, erro
 		restDays = []int{}
 	}
 
+	timezone := profile.Timezone
+	if timezone == "" {
+		timezone = helpers.DefaultTimezone
+	}
+
 	tx.Commit()
 
 	return dto.ProfileResponseDTO{
@@ -34,6 +41,7 @@ func (s *ProfileService) GetProfile(userID uint64) (dto.ProfileResponseDTO, erro
 		SplitType:          profile.SplitType,
 		Intensity:          profile.Intensity,
 		TargetWeight:       profile.TargetWeight,
+		BodyWeight:         profile.BodyWeight,
 		BMI:                profile.BMI,
 		BMICategory:        profile.BMICategory,
 		Frequency:          profile.Frequency,
@@ -41,24 +49,63 @@ func (s *ProfileService) GetProfile(userID uint64) (dto.ProfileResponseDTO, erro
 		Goal:               profile.Goal,
 		Equipment:          helpers.DecodeEquipment(profile.EquipmentJSON),
 		RestDays:           restDays,
+		Timezone:           timezone,
+		Limitations:        helpers.DecodeLimitations(profile.LimitationsJSON),
+	}, nil
+}
+
+// GetLimitationOptions lists the targets and severities a limitation can use.
+func (s *ProfileService) GetLimitationOptions() (dto.LimitationOptionsDTO, error) {
+	bodyParts, err := repositories.GetDistinctBodyParts()
+	if err != nil {
+		return dto.LimitationOptionsDTO{}, fmt.Errorf("failed to retrieve body parts: %w", err)
+	}
+	sort.Strings(bodyParts)
+
+	return dto.LimitationOptionsDTO{
+		BodyParts:        bodyParts,
+		MovementPatterns: helpers.MovementPatterns(),
+		Severities:       []string{helpers.LimitationSeverityAvoid, helpers.LimitationSeverityLimit},
 	}, nil
 }
 
-func (s *ProfileService) UpdateProfile(userID uint64, input dto.UpdateProfileDTO) error {
-	if !helpers.IsValidSplitType(input.SplitType) {
-		return errors.New("invalid split type")
+// UpdateProfile saves the profile and reports whether the active plan was generated from different inputs.
+func (s *ProfileService) UpdateProfile(userID uint64, input dto.UpdateProfileDTO) (dto.UpdateProfileResponseDTO, error) {
+	split, err := resolveSplit(userID, input.SplitType)
+	if err != nil {
+		return dto.UpdateProfileResponseDTO{}, errors.New("invalid split type")
 	}
+	input.SplitType = split.Name
 	if !helpers.IsValidGoal(input.Goal) {
-		return errors.New("invalid goal")
+		return dto.UpdateProfileResponseDTO{}, errors.New("invalid goal")
 	}
 	if !helpers.IsValidIntensity(input.Intensity) {
-		return errors.New("invalid intensity")
+		return dto.UpdateProfileResponseDTO{}, errors.New("invalid intensity")
 	}
 	if !helpers.IsValidBMICategory(input.BMICategory) {
-		return errors.New("invalid BMI category")
+		return dto.UpdateProfileResponseDTO{}, errors.New("invalid BMI category")
 	}
 	if !helpers.IsValidEquipmentList(input.Equipment) {
-		return errors.New("invalid equipment list")
+		return dto.UpdateProfileResponseDTO{}, errors.New("invalid equipment list")
+	}
+	for _, day := range input.RestDays {
+		if !helpers.IsValidDayNumber(day) {
+			return dto.UpdateProfileResponseDTO{}, errors.New("rest days must be weekdays from 1 (Monday) to 7 (Sunday)")
+		}
+	}
+	if input.Timezone == "" {
+		input.Timezone = helpers.DefaultTimezone
+	}
+	if !helpers.IsValidTimezone(input.Timezone) {
+		return dto.UpdateProfileResponseDTO{}, errors.New("invalid timezone")
+	}
+	limitations, err := normalizeLimitations(input.Limitations)
+	if err != nil {
+		return dto.UpdateProfileResponseDTO{}, err
+	}
+	limitationsJSON, err := helpers.EncodeLimitations(limitations)
+	if err != nil {
+		return dto.UpdateProfileResponseDTO{}, err
 	}
 
 	containsBodyOnly := false
@@ -74,12 +121,12 @@ func (s *ProfileService) UpdateProfile(userID uint64, input dto.UpdateProfileDTO
 
 	equipmentJSON, err := helpers.EncodeEquipment(input.Equipment)
 	if err != nil {
-		return err
+		return dto.UpdateProfileResponseDTO{}, err
 	}
 
 	restDaysJSONBytes, err := json.Marshal(input.RestDays)
 	if err != nil {
-		return err
+		return dto.UpdateProfileResponseDTO{}, err
 	}
 
 	profile := models.Profile{
@@ -87,6 +134,7 @@ func (s *ProfileService) UpdateProfile(userID uint64, input dto.UpdateProfileDTO
 		SplitType:          input.SplitType,
 		Intensity:          input.Intensity,
 		TargetWeight:       input.TargetWeight,
+		BodyWeight:         input.BodyWeight,
 		BMI:                input.BMI,
 		BMICategory:        input.BMICategory,
 		Frequency:          7 - len(input.RestDays),
@@ -94,6 +142,8 @@ func (s *ProfileService) UpdateProfile(userID uint64, input dto.UpdateProfileDTO
 		Goal:               input.Goal,
 		EquipmentJSON:      equipmentJSON,
 		RestDaysJSON:       string(restDaysJSONBytes),
+		LimitationsJSON:    limitationsJSON,
+		Timezone:           input.Timezone,
 	}
 
 	tx := config.DB.Begin()
@@ -102,20 +152,88 @@ func (s *ProfileService) UpdateProfile(userID uint64, input dto.UpdateProfileDTO
 	if err != nil {
 		if err := repositories.CreateProfile(tx, &profile); err != nil {
 			tx.Rollback()
-			return err
+			return dto.UpdateProfileResponseDTO{}, err
 		}
 		tx.Commit()
-		return nil
+		return dto.UpdateProfileResponseDTO{}, nil
 	}
 
 	profile.ID = existing.ID
 	if err := repositories.UpdateProfile(tx, &profile); err != nil {
 		tx.Rollback()
-		return err
+		return dto.UpdateProfileResponseDTO{}, err
 	}
 
 	tx.Commit()
-	return nil
+
+	var response dto.UpdateProfileResponseDTO
+	if generationInputsChanged(existing, &profile) {
+		_, err := repositories.GetActiveWorkoutPlanByUserID(userID)
+		response.PlanOutdated = err == nil
+	}
+	return response, nil
+}
+
+// generationInputsChanged reports whether a profile edit touches anything plan generation reads.
+func generationInputsChanged(before, after *models.Profile) bool {
+	return before.SplitType != after.SplitType ||
+		before.Intensity != after.Intensity ||
+		before.BMICategory != after.BMICategory ||
+		before.DurationPerSession != after.DurationPerSession ||
+		before.Goal != after.Goal ||
+		before.EquipmentJSON != after.EquipmentJSON ||
+		before.RestDaysJSON != after.RestDaysJSON ||
+		before.LimitationsJSON != after.LimitationsJSON
+}
+
+// normalizeLimitations validates limitations, spelling body parts as the exercise catalogue does
+// and dropping duplicates (the stricter severity wins).
+func normalizeLimitations(limitations []dto.Limitation) ([]dto.Limitation, error) {
+	if len(limitations) == 0 {
+		return nil, nil
+	}
+
+	knownParts, err := repositories.GetDistinctBodyParts()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve body parts: %w", err)
+	}
+
+	var result []dto.Limitation
+	index := map[string]int{}
+	for _, limitation := range limitations {
+		limitation.Type = strings.ToLower(strings.TrimSpace(limitation.Type))
+		limitation.Severity = strings.ToLower(strings.TrimSpace(limitation.Severity))
+		if !helpers.IsValidLimitationSeverity(limitation.Severity) {
+			return nil, errors.New("limitation severity must be avoid or limit")
+		}
+
+		switch limitation.Type {
+		case helpers.LimitationTypeBodyPart:
+			part, ok := matchBodyPart(knownParts, limitation.Target)
+			if !ok {
+				return nil, fmt.Errorf("unknown body part %q in limitations", limitation.Target)
+			}
+			limitation.Target = part
+		case helpers.LimitationTypeMovement:
+			limitation.Target = strings.ToLower(strings.TrimSpace(limitation.Target))
+			if !helpers.IsValidMovementPattern(limitation.Target) {
+				return nil, fmt.Errorf("unknown movement pattern %q; use one of %s", limitation.Target, strings.Join(helpers.MovementPatterns(), ", "))
+			}
+		default:
+			return nil, errors.New("limitation type must be body_part or movement")
+		}
+
+		key := limitation.Type + ":" + strings.ToLower(limitation.Target)
+		if i, ok := index[key]; ok {
+			if limitation.Severity == helpers.LimitationSeverityAvoid {
+				result[i].Severity = helpers.LimitationSeverityAvoid
+			}
+			continue
+		}
+		index[key] = len(result)
+		result = append(result, limitation)
+	}
+	return result, nil
 }
 
 func (s *ProfileService) DeleteProfile(userID uint64) error {
@@ -0,0 +1,254 @@
+package services
+
+import (
+	"fmt"
+	"time"
+
+	"wellnesspath/config"
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/models"
+	"wellnesspath/repositories"
+)
+
+type ProgressionService struct{}
+
+// GetProposals returns the prescription changes suggested by the user's most recent logged sessions.
+// Nothing is written until the user accepts them through ApplyProposals.
+func (s *ProgressionService) GetProposals(userID uint64) ([]dto.ProgressionProposal, error) {
+	plan, err := repositories.GetActiveWorkoutPlanByUserID(userID)
+	if err != nil {
+		return nil, fmt.Errorf("user has no active workout plan")
+	}
+
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve profile: %w", err)
+	}
+
+	var planExerciseIDs, exerciseIDs []uint64
+	for _, day := range plan.Days {
+		for _, ex := range day.Exercises {
+			planExerciseIDs = append(planExerciseIDs, ex.ID)
+			exerciseIDs = append(exerciseIDs, ex.ExerciseID)
+		}
+	}
+
+	proposals := []dto.ProgressionProposal{}
+	if len(planExerciseIDs) == 0 {
+		return proposals, nil
+	}
+
+	copies, err := planExerciseCopies(plan)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve earlier versions of the plan: %w", err)
+	}
+	loggedIDs := make([]uint64, 0, len(copies))
+	for id := range copies {
+		loggedIDs = append(loggedIDs, id)
+	}
+
+	logs, err := repositories.GetCompletedSessionSetsByPlanExerciseIDs(userID, loggedIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve session logs: %w", err)
+	}
+	for i := range logs {
+		logs[i].PlanExerciseID = copies[logs[i].PlanExerciseID]
+	}
+	logs = baseWeekSets(plan, logs, helpers.LoadUserLocation(profile.Timezone))
+
+	exMap, err := repositories.GetExercisesByIDs(exerciseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve exercise details: %w", err)
+	}
+
+	baseReps := helpers.DetermineReps(profile.Intensity, profile.Goal, profile.BMICategory)
+
+	for _, day := range plan.Days {
+		for _, ex := range day.Exercises {
+			if !helpers.IsRepsBased(ex) {
+				continue
+			}
+
+			current := helpers.Prescription{Reps: ex.Reps, Sets: ex.Sets, Load: ex.Load}
+			result, ok := helpers.ProposeProgression(profile.Goal, baseReps, current, latestSessionSets(logs, ex))
+			if !ok {
+				continue
+			}
+
+			name := ""
+			if detail, found := exMap[ex.ExerciseID]; found {
+				name = detail.Name
+			}
+
+			proposals = append(proposals, dto.ProgressionProposal{
+				PlanExerciseID: ex.ID,
+				ExerciseID:     ex.ExerciseID,
+				Name:           name,
+				DayNumber:      day.DayNumber,
+				Rule:           result.Rule,
+				Reason:         result.Reason,
+				Current:        toPrescriptionDTO(current),
+				Proposed:       toPrescriptionDTO(result.Proposed),
+			})
+		}
+	}
+
+	return proposals, nil
+}
+
+// ApplyProposals writes the accepted proposals and dismisses the rejected ones until the next logged session.
+// Accepting a proposal is an edit of the plan, so it may copy the active version (see models.WorkoutPlan);
+// the response then carries the plan exercise IDs of the copy.
+func (s *ProgressionService) ApplyProposals(userID uint64, req dto.ApplyProgressionRequest) (dto.ApplyProgressionResponse, error) {
+	if len(req.Accept) == 0 && len(req.Reject) == 0 {
+		return dto.ApplyProgressionResponse{}, helpers.NewBadRequestError("accept or reject at least one proposal")
+	}
+
+	proposals, err := s.GetProposals(userID)
+	if err != nil {
+		return dto.ApplyProgressionResponse{}, err
+	}
+
+	pending := make(map[uint64]dto.ProgressionProposal)
+	for _, p := range proposals {
+		pending[p.PlanExerciseID] = p
+	}
+
+	response := dto.ApplyProgressionResponse{
+		Applied:  []dto.ProgressionProposal{},
+		Rejected: []uint64{},
+	}
+
+	for _, ids := range [][]uint64{req.Accept, req.Reject} {
+		for _, id := range ids {
+			if _, ok := pending[id]; !ok {
+				return dto.ApplyProgressionResponse{}, helpers.NewBadRequestError(fmt.Sprintf("no pending proposal for plan exercise %d", id))
+			}
+		}
+	}
+
+	tx := config.DB.Begin()
+
+	planExerciseIDs := make(map[uint64]uint64)
+	for id := range pending {
+		planExerciseIDs[id] = id
+	}
+	if len(req.Accept) > 0 {
+		plan, err := repositories.GetActiveWorkoutPlanByUserID(userID)
+		if err != nil {
+			tx.Rollback()
+			return dto.ApplyProgressionResponse{}, fmt.Errorf("user has no active workout plan")
+		}
+		if _, planExerciseIDs, err = editableActivePlanTx(tx, plan); err != nil {
+			tx.Rollback()
+			return dto.ApplyProgressionResponse{}, err
+		}
+	}
+
+	for _, id := range req.Accept {
+		proposal := pending[id]
+		proposal.PlanExerciseID = planExerciseIDs[id]
+		p := proposal.Proposed
+		if err := repositories.UpdateWorkoutPlanExercisePrescription(tx, proposal.PlanExerciseID, p.Reps, p.Sets, p.Load); err != nil {
+			tx.Rollback()
+			return dto.ApplyProgressionResponse{}, fmt.Errorf("failed to apply progression: %w", err)
+		}
+		response.Applied = append(response.Applied, proposal)
+	}
+
+	for _, id := range req.Reject {
+		if err := repositories.DismissProgressionProposalTx(tx, planExerciseIDs[id]); err != nil {
+			tx.Rollback()
+			return dto.ApplyProgressionResponse{}, fmt.Errorf("failed to reject progression: %w", err)
+		}
+		response.Rejected = append(response.Rejected, planExerciseIDs[id])
+	}
+
+	if err := tx.Commit().Error; err != nil {
+		return dto.ApplyProgressionResponse{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return response, nil
+}
+
+// planExerciseCopies maps the plan exercises of plan, and the copies of them in earlier versions
+// that edits were made from, to the plan exercise in plan. Sets logged against an earlier copy
+// count for the exercise it became.
+func planExerciseCopies(plan models.WorkoutPlan) (map[uint64]uint64, error) {
+	current := make(map[uint64]uint64)
+	var originIDs []uint64
+	for _, day := range plan.Days {
+		for _, ex := range day.Exercises {
+			origin := ex.OriginPlanExerciseID
+			if origin == 0 {
+				origin = ex.ID
+			}
+			current[origin] = ex.ID
+			originIDs = append(originIDs, origin)
+		}
+	}
+
+	exercises, err := repositories.GetPlanExerciseCopies(originIDs)
+	if err != nil {
+		return nil, err
+	}
+
+	copies := make(map[uint64]uint64)
+	for _, ex := range exercises {
+		origin := ex.OriginPlanExerciseID
+		if origin == 0 {
+			origin = ex.ID
+		}
+		if id, ok := current[origin]; ok {
+			copies[ex.ID] = id
+		}
+	}
+	return copies, nil
+}
+
+// latestSessionSets picks the sets of the most recent session logged for a plan exercise since
+// its prescription last changed or a proposal for it was dismissed. Sets of an exercise it has
+// since been replaced with are skipped. logs must be ordered newest first. Sets are compared by
+// when the server stored them, since the completion time is supplied by the client.
+func latestSessionSets(logs []models.WorkoutSessionSet, ex models.WorkoutPlanExercise) []models.WorkoutSessionSet {
+	var since time.Time
+	for _, marker := range []*time.Time{ex.ProgressedAt, ex.ProposalDismissedAt} {
+		if marker != nil && marker.After(since) {
+			since = *marker
+		}
+	}
+
+	var sessionID uint64
+	var sets []models.WorkoutSessionSet
+	for _, set := range logs {
+		if set.PlanExerciseID != ex.ID || set.ExerciseID != ex.ExerciseID || !set.CreatedAt.After(since) {
+			continue
+		}
+		if sessionID == 0 {
+			sessionID = set.SessionID
+		}
+		if set.SessionID == sessionID {
+			sets = append(sets, set)
+		}
+	}
+	return sets
+}
+
+// baseWeekSets drops sets logged in intensification and deload weeks. Those sessions trained an
+// adjusted prescription, so they say nothing about whether the base prescription was met.
+func baseWeekSets(plan models.WorkoutPlan, logs []models.WorkoutSessionSet, loc *time.Location) []models.WorkoutSessionSet {
+	var result []models.WorkoutSessionSet
+	for _, set := range logs {
+		week, _, ok := helpers.PlanWeekForDate(plan, helpers.CivilDate(set.CompletedAt.In(loc)))
+		if ok && helpers.ModifiesPrescription(week.Phase) {
+			continue
+		}
+		result = append(result, set)
+	}
+	return result
+}
+
+func toPrescriptionDTO(p helpers.Prescription) dto.PrescriptionDTO {
+	return dto.PrescriptionDTO{Reps: p.Reps, Sets: p.Sets, Load: p.Load}
+}
@@ -0,0 +1,294 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"time"
+
+	"wellnesspath/config"
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/models"
+	"wellnesspath/repositories"
+
+	"gorm.io/gorm"
+)
+
+type SessionService struct{}
+
+// maxClockSkew is how far a client-supplied completion time may lie outside the session.
+const maxClockSkew = time.Minute
+
+func (s *SessionService) StartSession(userID uint64, req dto.StartSessionRequest) (dto.SessionResponse, error) {
+	plan, err := repositories.GetActiveWorkoutPlanByUserID(userID)
+	if err != nil {
+		return dto.SessionResponse{}, fmt.Errorf("user has no active workout plan")
+	}
+
+	day, err := repositories.GetWorkoutPlanDayByDayID(plan.ID, req.DayID)
+	if err != nil {
+		return dto.SessionResponse{}, helpers.NewBadRequestError("workout day is not part of your active plan")
+	}
+	if models.IsRestDay(day) {
+		return dto.SessionResponse{}, helpers.NewBadRequestError("cannot start a session on a rest day")
+	}
+
+	if _, err := repositories.GetInProgressSessionByUserID(userID); err == nil {
+		return dto.SessionResponse{}, helpers.NewBadRequestError("finish your current session before starting a new one")
+	}
+
+	session := models.WorkoutSession{
+		UserID:    userID,
+		PlanID:    plan.ID,
+		DayID:     day.ID,
+		Status:    models.SessionStatusInProgress,
+		StartedAt: time.Now(),
+		Note:      req.Note,
+	}
+
+	tx := config.DB.Begin()
+	if err := repositories.CreateWorkoutSessionTx(tx, &session); err != nil {
+		tx.Rollback()
+		return dto.SessionResponse{}, fmt.Errorf("failed to start session: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return dto.SessionResponse{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	response := buildSessionResponse(session, map[uint64]models.WorkoutPlanDay{day.ID: day}, nil)
+	response.PlanVersion = plan.Version
+	return response, nil
+}
+
+func (s *SessionService) LogSet(userID uint64, sessionID uint64, req dto.LogSetRequest) (dto.SessionSetResponse, error) {
+	session, err := repositories.GetWorkoutSessionByID(userID, sessionID)
+	if err != nil {
+		return dto.SessionSetResponse{}, errors.New("workout session not found")
+	}
+	if session.Status != models.SessionStatusInProgress {
+		return dto.SessionSetResponse{}, helpers.NewBadRequestError("session is already finished")
+	}
+
+	planExercise, err := repositories.GetWorkoutPlanExerciseByID(req.PlanExerciseID)
+	if err != nil || planExercise.DayID != session.DayID {
+		return dto.SessionSetResponse{}, helpers.NewBadRequestError("exercise is not part of this session's workout day")
+	}
+
+	tx := config.DB.Begin()
+
+	setNumber := req.SetNumber
+	if setNumber == 0 {
+		count, err := repositories.CountSessionSetsForPlanExercise(tx, session.ID, planExercise.ID)
+		if err != nil {
+			tx.Rollback()
+			return dto.SessionSetResponse{}, fmt.Errorf("failed to count logged sets: %w", err)
+		}
+		setNumber = int(count) + 1
+	}
+
+	completedAt := time.Now()
+	if req.CompletedAt != nil {
+		if req.CompletedAt.After(completedAt.Add(maxClockSkew)) || req.CompletedAt.Before(session.StartedAt.Add(-maxClockSkew)) {
+			tx.Rollback()
+			return dto.SessionSetResponse{}, helpers.NewBadRequestError("completedAt must be between the start of the session and now")
+		}
+		completedAt = *req.CompletedAt
+	}
+
+	set := models.WorkoutSessionSet{
+		SessionID:      session.ID,
+		PlanExerciseID: planExercise.ID,
+		ExerciseID:     planExercise.ExerciseID,
+		SetNumber:      setNumber,
+		Reps:           req.Reps,
+		Load:           req.Load,
+		RPE:            req.RPE,
+		CompletedAt:    completedAt,
+	}
+	if err := repositories.CreateWorkoutSessionSetTx(tx, &set); err != nil {
+		tx.Rollback()
+		return dto.SessionSetResponse{}, fmt.Errorf("failed to log set: %w", err)
+	}
+	isRecord, err := recordPersonalRecord(tx, userID, set)
+	if err != nil {
+		tx.Rollback()
+		return dto.SessionSetResponse{}, fmt.Errorf("failed to record personal record: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return dto.SessionSetResponse{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	exMap, err := repositories.GetExercisesByIDs([]uint64{set.ExerciseID})
+	if err != nil {
+		return dto.SessionSetResponse{}, fmt.Errorf("failed to retrieve exercise details: %w", err)
+	}
+
+	response := buildSessionSetResponse(set, exMap)
+	response.PersonalRecord = isRecord
+	return response, nil
+}
+
+// recordPersonalRecord stores set as a personal record when its estimated one-rep max beats every
+// earlier set of the exercise the user logged, and reports whether it did.
+func recordPersonalRecord(tx *gorm.DB, userID uint64, set models.WorkoutSessionSet) (bool, error) {
+	estimate := helpers.EstimateOneRM(set.Load, set.Reps, helpers.DefaultOneRMFormula)
+	if estimate == 0 {
+		return false, nil
+	}
+
+	previous, err := repositories.GetLoadedSessionSetsForExercise(tx, userID, set.ExerciseID, set.ID)
+	if err != nil {
+		return false, err
+	}
+	best := 0.0
+	if prior, ok := helpers.BestOneRMSets(previous, helpers.DefaultOneRMFormula)[set.ExerciseID]; ok {
+		best = helpers.EstimateOneRM(prior.Load, prior.Reps, helpers.DefaultOneRMFormula)
+	}
+	if estimate <= best {
+		return false, nil
+	}
+
+	record := models.PersonalRecord{
+		UserID:         userID,
+		ExerciseID:     set.ExerciseID,
+		SessionSetID:   set.ID,
+		Reps:           set.Reps,
+		Load:           set.Load,
+		EstimatedOneRM: estimate,
+		PreviousOneRM:  best,
+		Formula:        helpers.DefaultOneRMFormula,
+		AchievedAt:     set.CompletedAt,
+	}
+	return true, repositories.CreatePersonalRecordTx(tx, &record)
+}
+
+func (s *SessionService) FinishSession(userID uint64, sessionID uint64, req dto.FinishSessionRequest) (dto.SessionResponse, error) {
+	session, err := repositories.GetWorkoutSessionByID(userID, sessionID)
+	if err != nil {
+		return dto.SessionResponse{}, errors.New("workout session not found")
+	}
+	if session.Status != models.SessionStatusInProgress {
+		return dto.SessionResponse{}, helpers.NewBadRequestError("session is already finished")
+	}
+
+	finishedAt := time.Now()
+	session.Status = models.SessionStatusCompleted
+	session.FinishedAt = &finishedAt
+	if req.Note != "" {
+		session.Note = req.Note
+	}
+
+	tx := config.DB.Begin()
+	if err := repositories.FinishWorkoutSessionTx(tx, &session); err != nil {
+		tx.Rollback()
+		return dto.SessionResponse{}, fmt.Errorf("failed to finish session: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return dto.SessionResponse{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	responses, err := buildSessionResponses([]models.WorkoutSession{session})
+	if err != nil {
+		return dto.SessionResponse{}, err
+	}
+	return responses[0], nil
+}
+
+func (s *SessionService) GetSessionByID(userID uint64, sessionID uint64) (dto.SessionResponse, error) {
+	session, err := repositories.GetWorkoutSessionByID(userID, sessionID)
+	if err != nil {
+		return dto.SessionResponse{}, errors.New("workout session not found")
+	}
+
+	responses, err := buildSessionResponses([]models.WorkoutSession{session})
+	if err != nil {
+		return dto.SessionResponse{}, err
+	}
+	return responses[0], nil
+}
+
+// GetSessions lists the user's sessions; a non-zero planID narrows them to one plan version.
+func (s *SessionService) GetSessions(userID uint64, planID uint64) ([]dto.SessionResponse, error) {
+	sessions, err := repositories.GetWorkoutSessionsByUserID(userID, planID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve sessions: %w", err)
+	}
+
+	return buildSessionResponses(sessions)
+}
+
+// buildSessionResponses batches the plan, day and exercise lookups for a list of sessions.
+func buildSessionResponses(sessions []models.WorkoutSession) ([]dto.SessionResponse, error) {
+	var planIDs, dayIDs, exerciseIDs []uint64
+	for _, session := range sessions {
+		planIDs = append(planIDs, session.PlanID)
+		dayIDs = append(dayIDs, session.DayID)
+		for _, set := range session.Sets {
+			exerciseIDs = append(exerciseIDs, set.ExerciseID)
+		}
+	}
+
+	planMap, err := repositories.GetWorkoutPlansByIDs(planIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve workout plans: %w", err)
+	}
+
+	dayMap, err := repositories.GetWorkoutPlanDaysByIDs(dayIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve workout days: %w", err)
+	}
+
+	exMap, err := repositories.GetExercisesByIDs(exerciseIDs)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve exercise details: %w", err)
+	}
+
+	responses := make([]dto.SessionResponse, 0, len(sessions))
+	for _, session := range sessions {
+		response := buildSessionResponse(session, dayMap, exMap)
+		response.PlanVersion = planMap[session.PlanID].Version
+		responses = append(responses, response)
+	}
+	return responses, nil
+}
+
+func buildSessionResponse(session models.WorkoutSession, dayMap map[uint64]models.WorkoutPlanDay, exMap map[uint64]*models.Exercise) dto.SessionResponse {
+	day := dayMap[session.DayID]
+
+	sets := make([]dto.SessionSetResponse, 0, len(session.Sets))
+	for _, set := range session.Sets {
+		sets = append(sets, buildSessionSetResponse(set, exMap))
+	}
+
+	return dto.SessionResponse{
+		ID:         session.ID,
+		PlanID:     session.PlanID,
+		DayID:      session.DayID,
+		DayNumber:  day.DayNumber,
+		Focus:      day.Focus,
+		Status:     session.Status,
+		StartedAt:  session.StartedAt,
+		FinishedAt: session.FinishedAt,
+		Note:       session.Note,
+		Sets:       sets,
+	}
+}
+
+func buildSessionSetResponse(set models.WorkoutSessionSet, exMap map[uint64]*models.Exercise) dto.SessionSetResponse {
+	name := ""
+	if detail, ok := exMap[set.ExerciseID]; ok {
+		name = detail.Name
+	}
+
+	return dto.SessionSetResponse{
+		ID:             set.ID,
+		PlanExerciseID: set.PlanExerciseID,
+		ExerciseID:     set.ExerciseID,
+		Name:           name,
+		SetNumber:      set.SetNumber,
+		Reps:           set.Reps,
+		Load:           set.Load,
+		RPE:            set.RPE,
+		CompletedAt:    set.CompletedAt,
+	}
+}
@@ -0,0 +1,291 @@
+package services
+
+import (
+	"errors"
+	"fmt"
+	"strings"
+
+	"wellnesspath/config"
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/models"
+	"wellnesspath/repositories"
+)
+
+type SplitService struct{}
+
+// GetAvailableSplits lists every split the user can pick: built-in, global, then their own.
+func (s *SplitService) GetAvailableSplits(userID uint64) ([]dto.SplitDefinitionResponse, error) {
+	splits, err := repositories.GetSplitDefinitionsForUser(userID)
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve splits: %w", err)
+	}
+
+	var result []dto.SplitDefinitionResponse
+	for _, split := range helpers.BuiltInSplits() {
+		result = append(result, buildSplitResponse(split))
+	}
+	for _, split := range splits {
+		result = append(result, buildSplitResponse(toSplitDefinition(split)))
+	}
+	return result, nil
+}
+
+func (s *SplitService) GetGlobalSplits() ([]dto.SplitDefinitionResponse, error) {
+	splits, err := repositories.GetGlobalSplitDefinitions()
+	if err != nil {
+		return nil, fmt.Errorf("failed to retrieve splits: %w", err)
+	}
+
+	result := []dto.SplitDefinitionResponse{}
+	for _, split := range splits {
+		result = append(result, buildSplitResponse(toSplitDefinition(split)))
+	}
+	return result, nil
+}
+
+func (s *SplitService) CreateSplit(userID uint64, req dto.SplitDefinitionRequest) (dto.SplitDefinitionResponse, error) {
+	return createSplit(&userID, req)
+}
+
+func (s *SplitService) CreateGlobalSplit(req dto.SplitDefinitionRequest) (dto.SplitDefinitionResponse, error) {
+	return createSplit(nil, req)
+}
+
+func (s *SplitService) UpdateSplit(userID uint64, splitID uint64, req dto.SplitDefinitionRequest) (dto.SplitDefinitionResponse, error) {
+	split, err := repositories.GetSplitDefinitionByID(splitID)
+	if err != nil || split.OwnerID == nil || *split.OwnerID != userID {
+		return dto.SplitDefinitionResponse{}, errors.New("split not found")
+	}
+	return updateSplit(split, req, func(name string) (bool, error) {
+		profile, err := repositories.GetProfileByUserID(config.DB, userID)
+		return err == nil && strings.EqualFold(profile.SplitType, name), nil
+	})
+}
+
+func (s *SplitService) UpdateGlobalSplit(splitID uint64, req dto.SplitDefinitionRequest) (dto.SplitDefinitionResponse, error) {
+	split, err := repositories.GetSplitDefinitionByID(splitID)
+	if err != nil || split.OwnerID != nil {
+		return dto.SplitDefinitionResponse{}, errors.New("split not found")
+	}
+	return updateSplit(split, req, func(name string) (bool, error) {
+		count, err := repositories.CountProfilesBySplitType(name)
+		return count > 0, err
+	})
+}
+
+func (s *SplitService) DeleteSplit(userID uint64, splitID uint64) error {
+	split, err := repositories.GetSplitDefinitionByID(splitID)
+	if err != nil || split.OwnerID == nil || *split.OwnerID != userID {
+		return errors.New("split not found")
+	}
+
+	if profile, err := repositories.GetProfileByUserID(config.DB, userID); err == nil && strings.EqualFold(profile.SplitType, split.Name) {
+		return helpers.NewBadRequestError("your profile uses this split; choose another split first")
+	}
+	return repositories.DeleteSplitDefinition(split.ID)
+}
+
+func (s *SplitService) DeleteGlobalSplit(splitID uint64) error {
+	split, err := repositories.GetSplitDefinitionByID(splitID)
+	if err != nil || split.OwnerID != nil {
+		return errors.New("split not found")
+	}
+
+	count, err := repositories.CountProfilesBySplitType(split.Name)
+	if err != nil {
+		return err
+	}
+	if count > 0 {
+		return helpers.NewBadRequestError(fmt.Sprintf("%d profiles use this split and would no longer generate plans", count))
+	}
+	return repositories.DeleteSplitDefinition(split.ID)
+}
+
+// resolveSplit finds the split a profile refers to: the user's own split first, then a global
+// one, then the built-ins.
+func resolveSplit(userID uint64, name string) (helpers.SplitDefinition, error) {
+	if split, err := repositories.GetSplitDefinitionByName(userID, name); err == nil {
+		return toSplitDefinition(split), nil
+	}
+	if split, ok := helpers.BuiltInSplit(name); ok {
+		return split, nil
+	}
+	return helpers.SplitDefinition{}, helpers.NewBadRequestError(fmt.Sprintf("unknown split type %q", name))
+}
+
+func createSplit(ownerID *uint64, req dto.SplitDefinitionRequest) (dto.SplitDefinitionResponse, error) {
+	split := models.SplitDefinition{OwnerID: ownerID}
+	if err := applySplitRequest(&split, req); err != nil {
+		return dto.SplitDefinitionResponse{}, err
+	}
+
+	tx := config.DB.Begin()
+	if err := repositories.CreateSplitDefinitionTx(tx, &split); err != nil {
+		tx.Rollback()
+		return dto.SplitDefinitionResponse{}, fmt.Errorf("failed to create split: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return dto.SplitDefinitionResponse{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return buildSplitResponse(toSplitDefinition(split)), nil
+}
+
+// updateSplit applies req to split; inUse reports whether profiles still refer to the split by name,
+// in which case it cannot be renamed.
+func updateSplit(split models.SplitDefinition, req dto.SplitDefinitionRequest, inUse func(name string) (bool, error)) (dto.SplitDefinitionResponse, error) {
+	previousName := split.Name
+	if err := applySplitRequest(&split, req); err != nil {
+		return dto.SplitDefinitionResponse{}, err
+	}
+
+	if !strings.EqualFold(previousName, split.Name) {
+		used, err := inUse(previousName)
+		if err != nil {
+			return dto.SplitDefinitionResponse{}, err
+		}
+		if used {
+			return dto.SplitDefinitionResponse{}, helpers.NewBadRequestError("this split is in use; it cannot be renamed")
+		}
+	}
+
+	tx := config.DB.Begin()
+	if err := repositories.ReplaceSplitDefinitionTx(tx, &split); err != nil {
+		tx.Rollback()
+		return dto.SplitDefinitionResponse{}, fmt.Errorf("failed to update split: %w", err)
+	}
+	if err := tx.Commit().Error; err != nil {
+		return dto.SplitDefinitionResponse{}, fmt.Errorf("failed to commit transaction: %w", err)
+	}
+
+	return buildSplitResponse(toSplitDefinition(split)), nil
+}
+
+// applySplitRequest validates req and copies it onto split, normalising body part names
+// to the spelling used in the exercise catalogue.
+func applySplitRequest(split *models.SplitDefinition, req dto.SplitDefinitionRequest) error {
+	name := strings.TrimSpace(req.Name)
+	if name == "" {
+		return helpers.NewBadRequestError("split name is required")
+	}
+	if helpers.IsValidSplitType(name) {
+		return helpers.NewBadRequestError(fmt.Sprintf("%q is a built-in split name", name))
+	}
+	exists, err := repositories.SplitDefinitionNameExists(split.OwnerID, name, split.ID)
+	if err != nil {
+		return err
+	}
+	if exists {
+		return helpers.NewBadRequestError(fmt.Sprintf("a split named %q already exists", name))
+	}
+
+	knownParts, err := repositories.GetDistinctBodyParts()
+	if err != nil {
+		return fmt.Errorf("failed to retrieve body parts: %w", err)
+	}
+
+	var focuses []models.SplitFocus
+	for i, focusReq := range req.Focuses {
+		focusName := strings.TrimSpace(focusReq.Name)
+		if focusName == "" || strings.EqualFold(focusName, "Rest") {
+			return helpers.NewBadRequestError(fmt.Sprintf("focus %d needs a name other than Rest", i+1))
+		}
+
+		var bodyParts []string
+		for _, part := range focusReq.BodyParts {
+			canonical, ok := matchBodyPart(knownParts, part)
+			if !ok {
+				return helpers.NewBadRequestError(fmt.Sprintf("unknown body part %q in focus %s", part, focusName))
+			}
+			if !helpers.Contains(bodyParts, canonical) {
+				bodyParts = append(bodyParts, canonical)
+			}
+		}
+
+		focuses = append(focuses, models.SplitFocus{
+			Order:         i + 1,
+			Name:          focusName,
+			BodyPartsJSON: helpers.EncodeBodyParts(bodyParts),
+		})
+	}
+
+	minFrequency, maxFrequency := req.MinFrequency, req.MaxFrequency
+	if minFrequency == 0 {
+		minFrequency = 1
+	}
+	if maxFrequency == 0 {
+		maxFrequency = 7
+	}
+	if minFrequency > maxFrequency {
+		return helpers.NewBadRequestError("minFrequency must not exceed maxFrequency")
+	}
+	if req.FullCyclesOnly {
+		cycle := len(focuses)
+		if maxFrequency/cycle*cycle < minFrequency {
+			return helpers.NewBadRequestError(fmt.Sprintf("no frequency between %d and %d completes full %d-day cycles", minFrequency, maxFrequency, cycle))
+		}
+	}
+
+	split.Name = name
+	split.Description = req.Description
+	split.MinFrequency = minFrequency
+	split.MaxFrequency = maxFrequency
+	split.FullCyclesOnly = req.FullCyclesOnly
+	split.Focuses = focuses
+	return nil
+}
+
+func matchBodyPart(knownParts []string, part string) (string, bool) {
+	for _, known := range knownParts {
+		if strings.EqualFold(known, strings.TrimSpace(part)) {
+			return known, true
+		}
+	}
+	return "", false
+}
+
+func toSplitDefinition(split models.SplitDefinition) helpers.SplitDefinition {
+	scope := helpers.SplitScopeUser
+	if split.OwnerID == nil {
+		scope = helpers.SplitScopeGlobal
+	}
+
+	focuses := make([]helpers.FocusDefinition, 0, len(split.Focuses))
+	for _, focus := range split.Focuses {
+		focuses = append(focuses, helpers.FocusDefinition{
+			Name:      focus.Name,
+			BodyParts: helpers.DecodeBodyParts(focus.BodyPartsJSON),
+		})
+	}
+
+	return helpers.SplitDefinition{
+		ID:             split.ID,
+		Name:           split.Name,
+		Scope:          scope,
+		Focuses:        focuses,
+		MinFrequency:   split.MinFrequency,
+		MaxFrequency:   split.MaxFrequency,
+		FullCyclesOnly: split.FullCyclesOnly,
+	}
+}
+
+func buildSplitResponse(split helpers.SplitDefinition) dto.SplitDefinitionResponse {
+	response := dto.SplitDefinitionResponse{
+		ID:             split.ID,
+		Name:           split.Name,
+		Scope:          split.Scope,
+		MinFrequency:   split.MinFrequency,
+		MaxFrequency:   split.MaxFrequency,
+		FullCyclesOnly: split.FullCyclesOnly,
+		Focuses:        []dto.SplitFocusResponse{},
+	}
+	for i, focus := range split.Focuses {
+		response.Focuses = append(response.Focuses, dto.SplitFocusResponse{
+			Order:     i + 1,
+			Name:      focus.Name,
+			BodyParts: focus.BodyParts,
+		})
+	}
+	return response
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"fmt"
+	"sort"
+
+	"wellnesspath/config"
+	"wellnesspath/dto"
+	"wellnesspath/helpers"
+	"wellnesspath/repositories"
+)
+
+type StrengthService struct{}
+
+// GetStrengthProfile reports the best estimated one-rep max per exercise from the user's logged
+// sets, the reference lifts against the bodyweight standards, and the personal records set so far.
+// Without a body weight on the profile the lifts are listed without levels and no intensity change
+// is suggested; a target weight is a goal, not the user's current weight.
+func (s *StrengthService) GetStrengthProfile(userID uint64, formula string) (dto.StrengthProfileOutput, error) {
+	profile, err := repositories.GetProfileByUserID(config.DB, userID)
+	if err != nil {
+		return dto.StrengthProfileOutput{}, fmt.Errorf("failed to retrieve profile: %w", err)
+	}
+	bodyWeight := profile.BodyWeight
+
+	sets, err := repositories.GetLoadedSessionSetsByUserID(userID)
+	if err != nil {
+		return dto.StrengthProfileOutput{}, fmt.Errorf("failed to retrieve logged sets: %w", err)
+	}
+
+	records, err := repositories.GetPersonalRecordsByUserID(userID)
+	if err != nil {
+		return dto.StrengthProfileOutput{}, fmt.Errorf("failed to retrieve personal records: %w", err)
+	}
+
+	best := helpers.BestOneRMSets(sets, formula)
+	ids := make([]uint64, 0, len(best)+len(records))
+	for id := range best {
+		ids = append(ids, id)
+	}
+	for _, record := range records {
+		ids = append(ids, record.ExerciseID)
+	}
+
+	exMap, err := repositories.GetExercisesByIDs(ids)
+	if err != nil {
+		return dto.StrengthProfileOutput{}, fmt.Errorf("failed to retrieve exercise details: %w", err)
+	}
+
+	exercises := make([]dto.ExerciseStrength, 0, len(best))
+	for id, set := range best {
+		name := ""
+		if detail, ok := exMap[id]; ok {
+			name = detail.Name
+		}
+		estimate := helpers.EstimateOneRM(set.Load, set.Reps, formula)
+		exercises = append(exercises, dto.ExerciseStrength{
+			ExerciseID:       id,
+			Name:             name,
+			EstimatedOneRM:   estimate,
+			Load:             set.Load,
+			Reps:             set.Reps,
+			RelativeStrength: helpers.RelativeStrength(estimate, bodyWeight),
+			AchievedAt:       set.CompletedAt,
+		})
+	}
+	sort.Slice(exercises, func(i, j int) bool {
+		if exercises[i].EstimatedOneRM != exercises[j].EstimatedOneRM {
+			return exercises[i].EstimatedOneRM > exercises[j].EstimatedOneRM
+		}
+		return exercises[i].ExerciseID < exercises[j].ExerciseID
+	})
+
+	personalRecords := make([]dto.PersonalRecordResponse, 0, len(records))
+	for _, record := range records {
+		name := ""
+		if detail, ok := exMap[record.ExerciseID]; ok {
+			name = detail.Name
+		}
+		personalRecords = append(personalRecords, dto.PersonalRecordResponse{
+			ID:             record.ID,
+			ExerciseID:     record.ExerciseID,
+			Name:           name,
+			Load:           record.Load,
+			Reps:           record.Reps,
+			EstimatedOneRM: record.EstimatedOneRM,
+			PreviousOneRM:  record.PreviousOneRM,
+			Formula:        record.Formula,
+			AchievedAt:     record.AchievedAt,
+		})
+	}
+
+	standards := helpers.AssessStrengthStandards(exercises, bodyWeight)
+	output := dto.StrengthProfileOutput{
+		Formula:         formula,
+		BodyWeight:      bodyWeight,
+		Intensity:       profile.Intensity,
+		Exercises:       exercises,
+		Standards:       standards,
+		PersonalRecords: personalRecords,
+	}
+	if bodyWeight <= 0 {
+		output.Notice = "Record your body weight in your profile to compare your lifts with the strength standards."
+		return output, nil
+	}
+	output.Suggestion = helpers.SuggestIntensity(profile.Intensity, standards)
+	return output, nil
+}